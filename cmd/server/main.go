@@ -1,20 +1,30 @@
 package main
 
 import (
+	"context"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/meet-app/backend/internal/api/handlers"
 	"github.com/meet-app/backend/internal/api/middleware"
 	"github.com/meet-app/backend/internal/config"
+	"github.com/meet-app/backend/internal/lifecycle"
+	"github.com/meet-app/backend/internal/meetingcode"
 	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/moderation"
 	"github.com/meet-app/backend/internal/repository"
 	"github.com/meet-app/backend/internal/service"
 	"github.com/meet-app/backend/internal/sse"
 	"github.com/meet-app/backend/internal/websocket"
+	"github.com/meet-app/backend/pkg/auth"
 	"github.com/meet-app/backend/pkg/database"
+	"github.com/meet-app/backend/pkg/mail"
+	"github.com/meet-app/backend/pkg/oidc"
+	"github.com/meet-app/backend/pkg/ratelimit"
+	"github.com/meet-app/backend/pkg/storage"
 )
 
 func main() {
@@ -25,6 +35,9 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
 
 	// Set Gin mode
 	gin.SetMode(cfg.Server.GinMode)
@@ -46,7 +59,18 @@ func main() {
 		&models.User{},
 		&models.Meeting{},
 		&models.Participant{},
+		&models.ParticipantSession{},
 		&models.Message{},
+		&models.MessageReaction{},
+		&models.MeetingInvite{},
+		&models.UserToken{},
+		&models.LoginAttempt{},
+		&models.UserIdentity{},
+		&models.APIToken{},
+		&models.RecoveryCode{},
+		&models.Organization{},
+		&models.OrganizationMember{},
+		&models.AuditEvent{},
 	); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
@@ -57,17 +81,197 @@ func main() {
 	meetingRepo := repository.NewMeetingRepository(db)
 	participantRepo := repository.NewParticipantRepository(db)
 	messageRepo := repository.NewMessageRepository(db)
+	reactionRepo := repository.NewReactionRepository(db)
+	inviteRepo := repository.NewInviteRepository(db)
+	userTokenRepo := repository.NewUserTokenRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	identityRepo := repository.NewUserIdentityRepository(db)
+	apiTokenRepo := repository.NewAPITokenRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	organizationRepo := repository.NewOrganizationRepository(db)
+	auditEventRepo := repository.NewAuditEventRepository(db)
+
+	// Anonymous guest joins are limited per IP address
+	guestJoinLimiter := ratelimit.NewTokenBucket(
+		database.GetRedis(),
+		"ratelimit:guest:",
+		cfg.Guest.JoinRateLimitBurst,
+		float64(cfg.Guest.JoinRateLimitPerHour)/3600,
+	)
+
+	// Initialize chat rate limiting and moderation
+	chatLimiter := ratelimit.NewTokenBucket(
+		database.GetRedis(),
+		"ratelimit:chat:",
+		cfg.Chat.RateLimitBurst,
+		float64(cfg.Chat.RateLimitPerMinute)/60,
+	)
+
+	var chatModerators moderation.Chain
+	if wordList := moderation.NewWordListModerator(
+		cfg.Chat.BlockedWords,
+		moderation.ParseAction(cfg.Chat.BlockedWordsAction, moderation.ActionMask),
+	); wordList != nil {
+		chatModerators = append(chatModerators, wordList)
+	}
+	if cfg.Chat.ModerationWebhook != "" {
+		chatModerators = append(chatModerators, moderation.NewWebhookModerator(
+			cfg.Chat.ModerationWebhook,
+			time.Duration(cfg.Chat.WebhookTimeoutMS)*time.Millisecond,
+		))
+	}
+
+	meetingCodes, err := meetingcode.NewGenerator(cfg.Meeting.CodeAlphabet, cfg.Meeting.CodePattern)
+	if err != nil {
+		log.Fatalf("Invalid meeting code configuration: %v", err)
+	}
+
+	// Wrong meeting passcodes lock a user out of the meeting for a while
+	passcodeLockoutPeriod := time.Duration(cfg.Meeting.PasscodeLockoutMinutes) * time.Minute
+	passcodeLockout := ratelimit.NewLockout(
+		database.GetRedis(),
+		"lockout:passcode:",
+		cfg.Meeting.PasscodeMaxAttempts,
+		passcodeLockoutPeriod,
+		passcodeLockoutPeriod,
+	)
+
+	// Token signing keys; asymmetric keys rotate and are published as a JWKS
+	tokenKeys, err := auth.NewKeyring(&cfg.JWT)
+	if err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+	go tokenKeys.RunRotation(context.Background())
+
+	// Password changes revoke tokens issued earlier; entries outlive refresh tokens
+	tokenRevocations := auth.NewRevocationStore(
+		database.GetRedis(),
+		time.Duration(cfg.JWT.RefreshHours)*time.Hour,
+	)
+
+	objectStorage, err := storage.NewClient(&cfg.MinIO)
+	if err != nil {
+		log.Fatalf("Invalid MinIO configuration: %v", err)
+	}
+
+	// Failed logins back off per account and IP address, then lock the account
+	loginLimiter := service.NewLoginLimiter(database.GetRedis(), &cfg.Login)
+
+	// Logins of accounts with two-factor authentication wait here for a code
+	mfaChallenges := service.NewMFAChallengeStore(database.GetRedis(), &cfg.MFA)
+
+	// Single sign-on is optional; the provider is discovered on first use
+	var oidcProvider *oidc.Provider
+	if cfg.OIDC.Enabled() {
+		oidcProvider = oidc.NewProvider(&cfg.OIDC)
+	}
+
+	mailer, err := mail.New(&cfg.Mail)
+	if err != nil {
+		log.Fatalf("Invalid mail configuration: %v", err)
+	}
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, &cfg.JWT)
-	meetingService := service.NewMeetingService(meetingRepo, participantRepo)
-	messageService := service.NewMessageService(messageRepo, participantRepo)
+	auditService := service.NewAuditService(auditEventRepo, meetingRepo, &cfg.Audit)
+	go auditService.RunRetention(context.Background())
+
+	mfaService := service.NewMFAService(userRepo, recoveryCodeRepo, &cfg.MFA)
+	authService := service.NewAuthService(
+		userRepo,
+		userTokenRepo,
+		loginAttemptRepo,
+		tokenRevocations,
+		loginLimiter,
+		mfaService,
+		mfaChallenges,
+		auditService,
+		mailer,
+		&cfg.Account,
+		tokenKeys,
+		&cfg.JWT,
+	)
+	ssoService, err := service.NewSSOService(
+		oidcProvider,
+		userRepo,
+		identityRepo,
+		loginAttemptRepo,
+		auditService,
+		mfaChallenges,
+		database.GetRedis(),
+		&cfg.OIDC,
+		tokenKeys,
+		&cfg.JWT,
+	)
+	if err != nil {
+		log.Fatalf("Invalid OIDC configuration: %v", err)
+	}
+	userService := service.NewUserService(userRepo, tokenRevocations, objectStorage, &cfg.MinIO, tokenKeys, &cfg.JWT)
+	meetingService := service.NewMeetingService(
+		meetingRepo,
+		participantRepo,
+		inviteRepo,
+		userRepo,
+		organizationRepo,
+		auditService,
+		websocket.GetHub(),
+		meetingCodes,
+		passcodeLockout,
+		&cfg.Meeting,
+	)
+	guestService := service.NewGuestService(userRepo, meetingService, guestJoinLimiter, tokenKeys, &cfg.JWT)
+	go guestService.RunCleanup(context.Background())
+	apiTokenService := service.NewAPITokenService(apiTokenRepo, userRepo)
+	serviceAccountService := service.NewServiceAccountService(userRepo, apiTokenRepo)
+	organizationService := service.NewOrganizationService(organizationRepo, userRepo)
+	messageService := service.NewMessageService(
+		messageRepo,
+		meetingRepo,
+		participantRepo,
+		reactionRepo,
+		auditService,
+		chatLimiter,
+		chatModerators,
+		&cfg.Chat,
+	)
+	adminService := service.NewAdminService(
+		userRepo,
+		apiTokenRepo,
+		loginAttemptRepo,
+		meetingService,
+		auditService,
+		tokenRevocations,
+		websocket.GetHub(),
+		sse.GetHub(),
+	)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
+	keysHandler := handlers.NewKeysHandler(tokenKeys)
+	ssoHandler := handlers.NewSSOHandler(ssoService, cfg.OIDC.PostLoginURL)
+	guestHandler := handlers.NewGuestHandler(guestService)
+	userHandler := handlers.NewUserHandler(userService)
+	mfaHandler := handlers.NewMFAHandler(mfaService)
+	apiTokenHandler := handlers.NewAPITokenHandler(apiTokenService)
+	serviceAccountHandler := handlers.NewServiceAccountHandler(serviceAccountService)
+	organizationHandler := handlers.NewOrganizationHandler(organizationService)
+	adminHandler := handlers.NewAdminHandler(adminService, messageService)
+	auditHandler := handlers.NewAuditHandler(auditService)
 	meetingHandler := handlers.NewMeetingHandler(meetingService, messageService)
-	sseHandler := sse.NewHandler()
-	wsHandler := websocket.NewHandler(participantRepo)
+	messageHandler := handlers.NewMessageHandler(messageService)
+	sseHandler := sse.NewHandler(meetingRepo, participantRepo)
+	wsHandler := websocket.NewHandler(participantRepo, meetingService, messageService, auditService)
+
+	// Start background meeting lifecycle jobs
+	if cfg.Lifecycle.Enabled {
+		lifecycleWorker := lifecycle.NewWorker(
+			&cfg.Lifecycle,
+			database.GetRedis(),
+			websocket.GetHub(),
+			meetingService,
+			messageService,
+		)
+		go lifecycleWorker.Run(context.Background())
+	}
 
 	// Initialize router
 	router := gin.New()
@@ -94,6 +298,20 @@ func main() {
 		})
 	})
 
+	// Public keys that verify our access tokens
+	router.GET("/.well-known/jwks.json", keysHandler.JWKS)
+
+	// Personal API tokens are accepted alongside JWTs; routes require scopes
+	requireAuth := middleware.AuthMiddleware(tokenKeys, tokenRevocations, apiTokenService)
+	requireMeetingAuth := middleware.MeetingAuthMiddleware(tokenKeys, tokenRevocations, apiTokenService)
+	requireSession := middleware.RequireSession()
+	profileRead := middleware.RequireScope(models.ScopeProfileRead)
+	profileWrite := middleware.RequireScope(models.ScopeProfileWrite)
+	meetingsRead := middleware.RequireScope(models.ScopeMeetingsRead)
+	meetingsWrite := middleware.RequireScope(models.ScopeMeetingsWrite)
+	messagesRead := middleware.RequireScope(models.ScopeMessagesRead)
+	messagesWrite := middleware.RequireScope(models.ScopeMessagesWrite)
+
 	// API routes
 	api := router.Group("/api")
 	{
@@ -102,40 +320,141 @@ func main() {
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/login/mfa", authHandler.CompleteMFALogin)
 			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.POST("/forgot-password", authHandler.ForgotPassword)
+			auth.POST("/reset-password", authHandler.ResetPassword)
+			auth.POST("/verify-email", authHandler.VerifyEmail)
+			auth.GET("/oidc/login", ssoHandler.BeginLogin)
+			auth.GET("/oidc/callback", ssoHandler.Callback)
 
 			// Protected auth routes
 			authProtected := auth.Group("")
-			authProtected.Use(middleware.AuthMiddleware(&cfg.JWT))
+			authProtected.Use(requireAuth)
 			{
-				authProtected.GET("/me", authHandler.GetMe)
-				authProtected.POST("/logout", authHandler.Logout)
+				authProtected.GET("/me", profileRead, authHandler.GetMe)
+				authProtected.POST("/logout", requireSession, authHandler.Logout)
+				authProtected.POST("/verify-email/resend", requireSession, authHandler.ResendVerification)
 			}
 		}
 
+		// User profile routes (protected)
+		users := api.Group("/users")
+		users.Use(requireAuth)
+		{
+			users.PATCH("/me", profileWrite, userHandler.UpdateProfile)
+			users.POST("/me/password", requireSession, userHandler.ChangePassword)
+			users.POST("/me/avatar/upload-url", profileWrite, userHandler.CreateAvatarUpload)
+			users.POST("/me/avatar", profileWrite, userHandler.CompleteAvatarUpload)
+
+			// Personal API tokens are managed from a signed-in session only
+			users.GET("/me/tokens", requireSession, apiTokenHandler.ListTokens)
+			users.POST("/me/tokens", requireSession, apiTokenHandler.CreateToken)
+			users.DELETE("/me/tokens/:tokenId", requireSession, apiTokenHandler.RevokeToken)
+
+			// Two-factor authentication (session only)
+			users.GET("/me/mfa", requireSession, mfaHandler.GetStatus)
+			users.POST("/me/mfa/totp", requireSession, mfaHandler.BeginTOTPSetup)
+			users.POST("/me/mfa/totp/confirm", requireSession, mfaHandler.ConfirmTOTPSetup)
+			users.POST("/me/mfa/totp/disable", requireSession, mfaHandler.DisableTOTP)
+			users.POST("/me/mfa/recovery-codes", requireSession, mfaHandler.RegenerateRecoveryCodes)
+		}
+
+		// Service accounts and their tokens (session only)
+		serviceAccounts := api.Group("/service-accounts")
+		serviceAccounts.Use(requireAuth, requireSession)
+		{
+			serviceAccounts.POST("", serviceAccountHandler.CreateServiceAccount)
+			serviceAccounts.GET("", serviceAccountHandler.ListServiceAccounts)
+			serviceAccounts.DELETE("/:id", serviceAccountHandler.DeleteServiceAccount)
+			serviceAccounts.GET("/:id/tokens", apiTokenHandler.ListTokens)
+			serviceAccounts.POST("/:id/tokens", apiTokenHandler.CreateToken)
+			serviceAccounts.DELETE("/:id/tokens/:tokenId", apiTokenHandler.RevokeToken)
+		}
+
+		// Organization routes (protected); changes need a session
+		organizations := api.Group("/organizations")
+		organizations.Use(requireAuth)
+		{
+			organizations.POST("", requireSession, organizationHandler.CreateOrganization)
+			organizations.GET("", meetingsRead, organizationHandler.ListOrganizations)
+			organizations.GET("/:id", meetingsRead, organizationHandler.GetOrganization)
+			organizations.PATCH("/:id", requireSession, organizationHandler.UpdateOrganization)
+			organizations.DELETE("/:id", requireSession, organizationHandler.DeleteOrganization)
+			organizations.GET("/:id/members", meetingsRead, organizationHandler.ListMembers)
+			organizations.POST("/:id/members", requireSession, organizationHandler.AddMember)
+			organizations.PATCH("/:id/members/:userId", requireSession, organizationHandler.UpdateMemberRole)
+			organizations.DELETE("/:id/members/:userId", requireSession, organizationHandler.RemoveMember)
+			organizations.GET("/:id/meetings", meetingsRead, meetingHandler.ListOrganizationMeetings)
+		}
+
+		// Admin routes (deployment admins with a session only)
+		admin := api.Group("/admin")
+		admin.Use(requireAuth, requireSession, middleware.RequireAdmin(adminService))
+		{
+			admin.GET("/users", adminHandler.ListUsers)
+			admin.GET("/users/:id", adminHandler.GetUser)
+			admin.DELETE("/users/:id", adminHandler.DeleteUser)
+			admin.POST("/users/:id/suspend", adminHandler.SuspendUser)
+			admin.POST("/users/:id/unsuspend", adminHandler.UnsuspendUser)
+			admin.GET("/meetings/active", adminHandler.ListActiveMeetings)
+			admin.POST("/meetings/:id/end", adminHandler.EndMeeting)
+			admin.GET("/login-attempts", adminHandler.ListLoginAttempts)
+			admin.GET("/audit-events", auditHandler.ListEvents)
+		}
+
+		// Guest access (public): mints a token limited to one meeting
+		api.POST("/guest/join", guestHandler.GuestJoin)
+
+		// Meeting event stream, also open to the meeting's guests
+		api.GET("/meetings/:id/events", requireMeetingAuth, meetingsRead, sseHandler.Stream)
+
 		// Meeting routes (protected)
 		meetings := api.Group("/meetings")
-		meetings.Use(middleware.AuthMiddleware(&cfg.JWT))
+		meetings.Use(requireAuth)
 		{
-			meetings.POST("", meetingHandler.CreateMeeting)
-			meetings.POST("/join", meetingHandler.JoinMeeting)
-			meetings.GET("/code/:code", meetingHandler.GetMeetingByCode)
+			meetings.POST("", meetingsWrite, meetingHandler.CreateMeeting)
+			meetings.GET("", meetingsRead, meetingHandler.ListMeetings)
+			meetings.POST("/join", meetingsWrite, meetingHandler.JoinMeeting)
+			meetings.GET("/code/:code", meetingsRead, meetingHandler.GetMeetingByCode)
 
 			// Meeting ID-based routes
 			meetingByID := meetings.Group("/:id")
 			{
-				meetingByID.POST("/leave", meetingHandler.LeaveMeeting)
-				meetingByID.POST("/end", meetingHandler.EndMeeting)
-				meetingByID.GET("/participants", meetingHandler.GetMeetingParticipants)
-				meetingByID.POST("/messages", meetingHandler.SendMessage)
-				meetingByID.GET("/messages", meetingHandler.GetMessages)
-				meetingByID.GET("/events", sseHandler.Stream)
+				meetingByID.POST("/leave", meetingsWrite, meetingHandler.LeaveMeeting)
+				meetingByID.POST("/end", meetingsWrite, meetingHandler.EndMeeting)
+				meetingByID.POST("/regenerate-code", meetingsWrite, meetingHandler.RegenerateMeetingCode)
+				meetingByID.PATCH("/settings", meetingsWrite, meetingHandler.UpdateMeetingSettings)
+				meetingByID.PUT("/passcode", meetingsWrite, meetingHandler.SetMeetingPasscode)
+				meetingByID.POST("/invites", meetingsWrite, meetingHandler.CreateInvite)
+				meetingByID.GET("/invites", meetingsRead, meetingHandler.ListInvites)
+				meetingByID.DELETE("/invites/:inviteId", meetingsWrite, meetingHandler.RevokeInvite)
+				meetingByID.GET("/participants", meetingsRead, meetingHandler.GetMeetingParticipants)
+				meetingByID.PATCH("/participants/:userId/role", meetingsWrite, meetingHandler.UpdateParticipantRole)
+				meetingByID.GET("/attendance", meetingsRead, meetingHandler.GetAttendance)
+				meetingByID.GET("/attendance/export", meetingsRead, meetingHandler.ExportAttendance)
+				meetingByID.GET("/audit-events", meetingsRead, auditHandler.ListMeetingEvents)
+				meetingByID.POST("/messages", messagesWrite, meetingHandler.SendMessage)
+				meetingByID.GET("/messages", messagesRead, meetingHandler.GetMessages)
+				meetingByID.GET("/messages/flagged", messagesRead, meetingHandler.GetFlaggedMessages)
+				meetingByID.GET("/messages/export", messagesRead, meetingHandler.ExportMessages)
+				meetingByID.DELETE("/messages/:messageId", messagesWrite, meetingHandler.DeleteMessage)
+				meetingByID.GET("/messages/:messageId/replies", messagesRead, meetingHandler.GetReplies)
+				meetingByID.POST("/messages/:messageId/reactions", messagesWrite, meetingHandler.AddReaction)
+				meetingByID.DELETE("/messages/:messageId/reactions/:emoji", messagesWrite, meetingHandler.RemoveReaction)
 			}
 		}
+
+		// Message routes (protected)
+		messages := api.Group("/messages")
+		messages.Use(requireAuth)
+		{
+			messages.GET("/search", messagesRead, messageHandler.SearchMessages)
+		}
 	}
 
-	// WebSocket endpoint (protected)
-	router.GET("/ws", middleware.AuthMiddleware(&cfg.JWT), wsHandler.HandleWebSocket)
+	// WebSocket endpoint (protected, also open to the meeting's guests)
+	router.GET("/ws", requireMeetingAuth, meetingsWrite, wsHandler.HandleWebSocket)
 
 	// ==========================================
 	// SERVE FRONTEND STATIC FILES
@@ -0,0 +1,428 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/internal/service"
+	"github.com/meet-app/backend/internal/sse"
+)
+
+// endReasonAdmin is the meeting_ended reason when an admin ended the meeting
+const endReasonAdmin = "admin"
+
+type AdminHandler struct {
+	adminService   service.AdminService
+	messageService service.MessageService
+}
+
+func NewAdminHandler(adminService service.AdminService, messageService service.MessageService) *AdminHandler {
+	return &AdminHandler{
+		adminService:   adminService,
+		messageService: messageService,
+	}
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason"` // Optional, shown to admins only
+}
+
+// ActiveMeetingResponse is an active meeting with its live connections
+type ActiveMeetingResponse struct {
+	models.MeetingResponse
+	WebSocketConnections   int `json:"websocket_connections"`
+	EventStreamConnections int `json:"event_stream_connections"`
+}
+
+// ListUsers godoc
+// @Summary List users
+// @Description List and search all users, newest first (admins only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Search email, username and name"
+// @Param role query string false "Deployment role (member, admin)"
+// @Param suspended query bool false "Only suspended (true) or active (false) users"
+// @Param limit query int false "Maximum number of users" default(50)
+// @Param offset query int false "Number of users to skip" default(0)
+// @Success 200 {array} models.AdminUserResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	params := repository.UserListParams{
+		Query: c.Query("q"),
+		Role:  models.UserRole(c.Query("role")),
+	}
+
+	if suspendedStr := c.Query("suspended"); suspendedStr != "" {
+		suspended, err := strconv.ParseBool(suspendedStr)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid suspended filter")
+			return
+		}
+		params.Suspended = &suspended
+	}
+
+	params.Limit, params.Offset = pageParams(c)
+
+	users, err := h.adminService.ListUsers(params)
+	if err != nil {
+		if err == service.ErrInvalidListFilter {
+			middleware.RespondWithError(c, http.StatusBadRequest, "role must be member or admin")
+			return
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to list users")
+		return
+	}
+
+	responses := make([]models.AdminUserResponse, len(users))
+	for i := range users {
+		responses[i] = users[i].ToAdminResponse()
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetUser godoc
+// @Summary Get a user
+// @Description Get any user with their account state (admins only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /admin/users/{id} [get]
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := h.adminService.GetUser(userID)
+	if err != nil {
+		respondAdminUserError(c, err, "Failed to get user")
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToAdminResponse())
+}
+
+// SuspendUser godoc
+// @Summary Suspend a user
+// @Description Block the user from logging in, sign out all their sessions, stop their API tokens (and those of service accounts they own) and close their WebSocket connections (admins only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body SuspendUserRequest false "Suspension reason"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /admin/users/{id}/suspend [post]
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	adminID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// The body is optional
+	var req SuspendUserRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	user, err := h.adminService.SuspendUser(adminID, userID, req.Reason, clientInfo(c))
+	if err != nil {
+		respondAdminUserError(c, err, "Failed to suspend user")
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToAdminResponse())
+}
+
+// UnsuspendUser godoc
+// @Summary Lift a user's suspension
+// @Description Let a suspended user log in again; sessions ended by the suspension stay signed out (admins only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.AdminUserResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /admin/users/{id}/unsuspend [post]
+func (h *AdminHandler) UnsuspendUser(c *gin.Context) {
+	adminID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := h.adminService.UnsuspendUser(adminID, userID, clientInfo(c))
+	if err != nil {
+		respondAdminUserError(c, err, "Failed to unsuspend user")
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToAdminResponse())
+}
+
+// DeleteUser godoc
+// @Summary Delete a user
+// @Description Sign the user out everywhere, revoke their API tokens and delete the account. Meetings they hosted are kept (admins only).
+// @Tags admin
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 204
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /admin/users/{id} [delete]
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	adminID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.adminService.DeleteUser(adminID, userID, clientInfo(c)); err != nil {
+		respondAdminUserError(c, err, "Failed to delete user")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListActiveMeetings godoc
+// @Summary List active meetings
+// @Description List the active meetings with their open WebSocket and event stream connections on this server (admins only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} ActiveMeetingResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /admin/meetings/active [get]
+func (h *AdminHandler) ListActiveMeetings(c *gin.Context) {
+	meetings, err := h.adminService.ListActiveMeetings()
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to list active meetings")
+		return
+	}
+
+	responses := make([]ActiveMeetingResponse, len(meetings))
+	for i := range meetings {
+		responses[i] = ActiveMeetingResponse{
+			MeetingResponse:        meetings[i].Meeting.ToResponse(),
+			WebSocketConnections:   meetings[i].SignalingConnections,
+			EventStreamConnections: meetings[i].EventStreams,
+		}
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// EndMeeting godoc
+// @Summary Force-end a meeting
+// @Description End any meeting and disconnect everyone in it (admins only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /admin/meetings/{id}/end [post]
+func (h *AdminHandler) EndMeeting(c *gin.Context) {
+	adminID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	meeting, err := h.adminService.EndMeeting(adminID, meetingID, clientInfo(c))
+	if err != nil {
+		switch err {
+		case repository.ErrMeetingNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Meeting not found")
+		case service.ErrMeetingEnded:
+			middleware.RespondWithError(c, http.StatusConflict, "Meeting has already ended")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to end meeting")
+		}
+		return
+	}
+
+	// The admin is usually not in the meeting, so the notice is posted as the host
+	if _, err := h.messageService.SendSystemMessage(meeting.ID, meeting.HostID, models.SystemMessageMeetingEndedByAdmin, nil); err != nil {
+		log.Printf("Failed to post system message %s to meeting %s: %v", models.SystemMessageMeetingEndedByAdmin, meeting.ID, err)
+	}
+
+	sse.GetHub().BroadcastToMeeting(meeting.ID, sse.Event{
+		Type: sse.EventMeetingEnded,
+		Data: map[string]string{
+			"meeting_id": meeting.ID.String(),
+			"reason":     endReasonAdmin,
+		},
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Meeting ended successfully"})
+}
+
+// ListLoginAttempts godoc
+// @Summary Read the login audit log
+// @Description List successful and failed logins, newest first (admins only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string false "Only attempts on this user"
+// @Param email query string false "Only attempts with this email"
+// @Param ip query string false "Only attempts from this IP address"
+// @Param success query bool false "Only successful (true) or failed (false) attempts"
+// @Param from query string false "Earliest attempt time (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Latest attempt time (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Maximum number of attempts" default(50)
+// @Param offset query int false "Number of attempts to skip" default(0)
+// @Success 200 {array} models.LoginAttempt
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /admin/login-attempts [get]
+func (h *AdminHandler) ListLoginAttempts(c *gin.Context) {
+	params := repository.LoginAttemptListParams{
+		Email:     c.Query("email"),
+		IPAddress: c.Query("ip"),
+	}
+
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+		params.UserID = &userID
+	}
+
+	if successStr := c.Query("success"); successStr != "" {
+		success, err := strconv.ParseBool(successStr)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid success filter")
+			return
+		}
+		params.Success = &success
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := parseTimeParam(fromStr, false)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid from date")
+			return
+		}
+		params.From = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := parseTimeParam(toStr, true)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid to date")
+			return
+		}
+		params.To = &to
+	}
+
+	params.Limit, params.Offset = pageParams(c)
+
+	attempts, err := h.adminService.ListLoginAttempts(params)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to list login attempts")
+		return
+	}
+
+	c.JSON(http.StatusOK, attempts)
+}
+
+// pageParams reads the limit and offset query parameters; malformed values
+// are ignored
+func pageParams(c *gin.Context) (limit, offset int) {
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil {
+			offset = o
+		}
+	}
+	return limit, offset
+}
+
+// respondAdminUserError maps admin user management errors to responses
+func respondAdminUserError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case repository.ErrUserNotFound:
+		middleware.RespondWithError(c, http.StatusNotFound, "User not found")
+	case service.ErrCannotModerateSelf, service.ErrInvalidSuspensionReason:
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+	default:
+		middleware.RespondWithError(c, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/internal/service"
+)
+
+// APITokenHandler manages the personal API tokens of the current user and,
+// under /service-accounts/:id, of the service accounts they own
+type APITokenHandler struct {
+	apiTokenService service.APITokenService
+}
+
+func NewAPITokenHandler(apiTokenService service.APITokenService) *APITokenHandler {
+	return &APITokenHandler{
+		apiTokenService: apiTokenService,
+	}
+}
+
+type CreateAPITokenRequest struct {
+	Name          string            `json:"name" binding:"required,max=100"`
+	Scopes        []models.APIScope `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int               `json:"expires_in_days"` // 0 never expires
+}
+
+// CreateToken godoc
+// @Summary Create an API token
+// @Description Create a scoped personal API token for the current user or one of their service accounts. The token is only returned once.
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string false "Service account ID"
+// @Param request body CreateAPITokenRequest true "Token name, scopes and expiry"
+// @Success 201 {object} models.APITokenResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /users/me/tokens [post]
+// @Router /service-accounts/{id}/tokens [post]
+func (h *APITokenHandler) CreateToken(c *gin.Context) {
+	callerID, userID, ok := tokenOwnerFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var req CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	expiresIn := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	token, plaintext, err := h.apiTokenService.CreateToken(callerID, userID, req.Name, req.Scopes, expiresIn)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidTokenName, service.ErrInvalidScopes, service.ErrInvalidTokenExpiry:
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		case service.ErrServiceAccountNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Service account not found")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to create token")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, token.ToResponse(plaintext))
+}
+
+// ListTokens godoc
+// @Summary List API tokens
+// @Description List the unrevoked API tokens of the current user or one of their service accounts
+// @Tags tokens
+// @Produce json
+// @Security BearerAuth
+// @Param id path string false "Service account ID"
+// @Success 200 {array} models.APITokenResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /users/me/tokens [get]
+// @Router /service-accounts/{id}/tokens [get]
+func (h *APITokenHandler) ListTokens(c *gin.Context) {
+	callerID, userID, ok := tokenOwnerFromRequest(c)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.apiTokenService.ListTokens(callerID, userID)
+	if err != nil {
+		if err == service.ErrServiceAccountNotFound {
+			middleware.RespondWithError(c, http.StatusNotFound, "Service account not found")
+			return
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to list tokens")
+		return
+	}
+
+	responses := make([]models.APITokenResponse, len(tokens))
+	for i := range tokens {
+		responses[i] = tokens[i].ToResponse("")
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// RevokeToken godoc
+// @Summary Revoke an API token
+// @Description Revoke an API token of the current user or one of their service accounts. It stops working immediately.
+// @Tags tokens
+// @Security BearerAuth
+// @Param id path string false "Service account ID"
+// @Param tokenId path string true "Token ID"
+// @Success 204
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /users/me/tokens/{tokenId} [delete]
+// @Router /service-accounts/{id}/tokens/{tokenId} [delete]
+func (h *APITokenHandler) RevokeToken(c *gin.Context) {
+	callerID, userID, ok := tokenOwnerFromRequest(c)
+	if !ok {
+		return
+	}
+
+	tokenID, err := uuid.Parse(c.Param("tokenId"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid token ID")
+		return
+	}
+
+	if err := h.apiTokenService.RevokeToken(callerID, userID, tokenID); err != nil {
+		switch err {
+		case service.ErrServiceAccountNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Service account not found")
+		case repository.ErrAPITokenNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Token not found")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to revoke token")
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// tokenOwnerFromRequest returns the caller and the user whose tokens are
+// managed: the service account in the :id path parameter, or the caller
+func tokenOwnerFromRequest(c *gin.Context) (callerID, userID uuid.UUID, ok bool) {
+	callerID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if c.Param("id") == "" {
+		return callerID, callerID, true
+	}
+
+	userID, err = uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid service account ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return callerID, userID, true
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/internal/service"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListMeetingEvents godoc
+// @Summary Read a meeting's audit log
+// @Description List the security and moderation events of a meeting, newest first (host only)
+// @Tags meetings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param action query string false "Only events of this action, e.g. join.approved"
+// @Param actor_id query string false "Only events by this user"
+// @Param target_user_id query string false "Only events on this user"
+// @Param from query string false "Earliest event time (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Latest event time (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Maximum number of events" default(50)
+// @Param offset query int false "Number of events to skip" default(0)
+// @Success 200 {array} models.AuditEvent
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/audit-events [get]
+func (h *AuditHandler) ListMeetingEvents(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	params, ok := auditEventListParamsFromQuery(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.auditService.ListMeetingEvents(meetingID, userID, params)
+	if err != nil {
+		switch err {
+		case repository.ErrMeetingNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Meeting not found")
+		case service.ErrUnauthorizedAccess:
+			middleware.RespondWithError(c, http.StatusForbidden, "Only the host can read the audit log")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to list audit events")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// ListEvents godoc
+// @Summary Read the audit log
+// @Description List security and moderation events across all meetings and users, newest first (admins only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param action query string false "Only events of this action, e.g. auth.login_failed"
+// @Param actor_id query string false "Only events by this user"
+// @Param target_user_id query string false "Only events on this user"
+// @Param meeting_id query string false "Only events in this meeting"
+// @Param from query string false "Earliest event time (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Latest event time (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Maximum number of events" default(50)
+// @Param offset query int false "Number of events to skip" default(0)
+// @Success 200 {array} models.AuditEvent
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /admin/audit-events [get]
+func (h *AuditHandler) ListEvents(c *gin.Context) {
+	params, ok := auditEventListParamsFromQuery(c)
+	if !ok {
+		return
+	}
+
+	if meetingIDStr := c.Query("meeting_id"); meetingIDStr != "" {
+		meetingID, err := uuid.Parse(meetingIDStr)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+			return
+		}
+		params.MeetingID = &meetingID
+	}
+
+	events, err := h.auditService.ListEvents(params)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to list audit events")
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// auditEventListParamsFromQuery reads the audit log filters shared by both
+// listings, responding with 400 when one is malformed
+func auditEventListParamsFromQuery(c *gin.Context) (repository.AuditEventListParams, bool) {
+	params := repository.AuditEventListParams{
+		Action: models.AuditAction(c.Query("action")),
+	}
+
+	if actorIDStr := c.Query("actor_id"); actorIDStr != "" {
+		actorID, err := uuid.Parse(actorIDStr)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid actor ID")
+			return params, false
+		}
+		params.ActorID = &actorID
+	}
+
+	if targetIDStr := c.Query("target_user_id"); targetIDStr != "" {
+		targetID, err := uuid.Parse(targetIDStr)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid target user ID")
+			return params, false
+		}
+		params.TargetUserID = &targetID
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := parseTimeParam(fromStr, false)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid from date")
+			return params, false
+		}
+		params.From = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := parseTimeParam(toStr, true)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid to date")
+			return params, false
+		}
+		params.To = &to
+	}
+
+	params.Limit, params.Offset = pageParams(c)
+	return params, true
+}
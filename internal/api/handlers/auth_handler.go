@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/meet-app/backend/internal/api/middleware"
@@ -31,6 +34,19 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type CompleteMFALoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // TOTP or recovery code
+}
+
+// MFAChallengeResponse is returned by login instead of tokens when the
+// account has two-factor authentication
+type MFAChallengeResponse struct {
+	MFARequired    bool   `json:"mfa_required"`
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresAt      string `json:"expires_at"`
+}
+
 type AuthResponse struct {
 	User         interface{} `json:"user"`
 	AccessToken  string      `json:"access_token"`
@@ -56,7 +72,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	user, tokens, err := h.authService.Register(req.Email, req.Username, req.Password, req.Name)
+	user, tokens, err := h.authService.Register(req.Email, req.Username, req.Password, req.Name, clientInfo(c))
 	if err != nil {
 		if err == repository.ErrEmailAlreadyExists {
 			middleware.RespondWithError(c, http.StatusConflict, "Email already exists")
@@ -84,7 +100,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary Login user
-// @Description Authenticate user with email and password
+// @Description Authenticate user with email and password. Repeated failures are throttled per account and IP address. Accounts with two-factor authentication get an MFAChallengeResponse to complete at /auth/login/mfa.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -92,6 +108,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Success 200 {object} AuthResponse
 // @Failure 400 {object} middleware.ErrorResponse
 // @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 429 {object} middleware.ErrorResponse
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -101,12 +119,28 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, tokens, err := h.authService.Login(req.Email, req.Password)
+	user, tokens, err := h.authService.Login(req.Email, req.Password, clientInfo(c))
 	if err != nil {
+		var mfaErr *service.MFARequiredError
+		if errors.As(err, &mfaErr) {
+			c.JSON(http.StatusOK, MFAChallengeResponse{
+				MFARequired:    true,
+				ChallengeToken: mfaErr.ChallengeToken,
+				ExpiresAt:      mfaErr.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+			})
+			return
+		}
+		if respondLoginThrottled(c, err) {
+			return
+		}
 		if err == service.ErrInvalidCredentials {
 			middleware.RespondWithError(c, http.StatusUnauthorized, "Invalid email or password")
 			return
 		}
+		if err == service.ErrAccountSuspended {
+			middleware.RespondWithError(c, http.StatusForbidden, "Account is suspended")
+			return
+		}
 		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to login")
 		return
 	}
@@ -119,6 +153,63 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	})
 }
 
+// CompleteMFALogin godoc
+// @Summary Complete a two-factor login
+// @Description Exchange the challenge token from login and a TOTP or recovery code for session tokens. Wrong codes count as failed logins.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body CompleteMFALoginRequest true "Challenge token and code"
+// @Success 200 {object} AuthResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 429 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /auth/login/mfa [post]
+func (h *AuthHandler) CompleteMFALogin(c *gin.Context) {
+	var req CompleteMFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, tokens, err := h.authService.CompleteMFALogin(req.ChallengeToken, req.Code, clientInfo(c))
+	if err != nil {
+		if respondLoginThrottled(c, err) {
+			return
+		}
+		switch err {
+		case service.ErrInvalidMFAChallenge, service.ErrInvalidMFACode:
+			middleware.RespondWithError(c, http.StatusUnauthorized, err.Error())
+		case service.ErrAccountSuspended:
+			middleware.RespondWithError(c, http.StatusForbidden, "Account is suspended")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to login")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, AuthResponse{
+		User:         user.ToResponse(),
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// respondLoginThrottled answers 429 with Retry-After if err is a
+// *service.LoginThrottledError
+func respondLoginThrottled(c *gin.Context, err error) bool {
+	var throttledErr *service.LoginThrottledError
+	if !errors.As(err, &throttledErr) {
+		return false
+	}
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttledErr.RetryAfter.Seconds()))))
+	middleware.RespondWithError(c, http.StatusTooManyRequests, "Too many failed login attempts, try again later")
+	return true
+}
+
 // GetMe godoc
 // @Summary Get current user
 // @Description Get the currently authenticated user's information
@@ -195,3 +286,157 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		"message": "Logged out successfully",
 	})
 }
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Email a password reset link. The response is the same whether or not the address has an account. Repeated requests are throttled per address and IP address.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ForgotPasswordRequest true "Forgot password request"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 429 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.authService.ForgotPassword(req.Email, clientInfo(c)); err != nil {
+		var throttledErr *service.LoginThrottledError
+		if errors.As(err, &throttledErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttledErr.RetryAfter.Seconds()))))
+			middleware.RespondWithError(c, http.StatusTooManyRequests, "Too many password reset requests, try again later")
+			return
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to request password reset")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "If an account exists for this email, a reset link has been sent",
+	})
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password using a reset link token. Signs out every existing session.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "Reset password request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.authService.ResetPassword(req.Token, req.NewPassword); err != nil {
+		switch err {
+		case service.ErrInvalidUserToken, service.ErrWeakPassword:
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to reset password")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password has been reset",
+	})
+}
+
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Confirm an email address using the token from a verification link
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body VerifyEmailRequest true "Verify email request"
+// @Success 200 {object} models.UserResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.authService.VerifyEmail(req.Token)
+	if err != nil {
+		if err == service.ErrInvalidUserToken {
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to verify email")
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToResponse())
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Send a new verification link to the current user's address, invalidating earlier links
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} map[string]string
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /auth/verify-email/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.authService.ResendVerificationEmail(userID); err != nil {
+		if err == service.ErrAlreadyVerified {
+			middleware.RespondWithError(c, http.StatusConflict, err.Error())
+			return
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to send verification email")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Verification email sent",
+	})
+}
+
+// clientInfo describes the client that sent the request, for the login and
+// audit logs
+func clientInfo(c *gin.Context) service.ClientInfo {
+	return service.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		RequestID: middleware.GetRequestIDFromContext(c),
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/internal/service"
+)
+
+type GuestHandler struct {
+	guestService service.GuestService
+}
+
+func NewGuestHandler(guestService service.GuestService) *GuestHandler {
+	return &GuestHandler{
+		guestService: guestService,
+	}
+}
+
+type GuestJoinRequest struct {
+	Code     string `json:"code" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Passcode string `json:"passcode"`
+}
+
+type GuestJoinResponse struct {
+	User        models.UserResponse    `json:"user"`
+	Meeting     models.MeetingResponse `json:"meeting"`
+	AccessToken string                 `json:"access_token"`
+	ExpiresAt   string                 `json:"expires_at"`
+}
+
+// GuestJoin godoc
+// @Summary Join a meeting as a guest
+// @Description Get a short-lived guest token for a meeting that allows guests. The token only works for the meeting's WebSocket and event stream, and guests always wait for the host to admit them.
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Param request body GuestJoinRequest true "Guest join request"
+// @Success 201 {object} GuestJoinResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 410 {object} middleware.ErrorResponse
+// @Failure 429 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /guest/join [post]
+func (h *GuestHandler) GuestJoin(c *gin.Context) {
+	var req GuestJoinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	session, err := h.guestService.JoinAsGuest(req.Code, req.Name, req.Passcode, c.ClientIP())
+	if err != nil {
+		var throttledErr *service.GuestJoinThrottledError
+		if errors.As(err, &throttledErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttledErr.RetryAfter.Seconds()))))
+			middleware.RespondWithError(c, http.StatusTooManyRequests, "Too many guest joins, try again later")
+			return
+		}
+		if respondJoinAccessError(c, err) {
+			return
+		}
+		switch err {
+		case repository.ErrMeetingNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Meeting not found")
+		case service.ErrGuestsNotAllowed:
+			middleware.RespondWithError(c, http.StatusForbidden, "Meeting does not allow guests")
+		case service.ErrMeetingEnded:
+			middleware.RespondWithError(c, http.StatusGone, "Meeting has ended")
+		case service.ErrInvalidDisplayName:
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to join as guest")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, GuestJoinResponse{
+		User:        session.User.ToResponse(),
+		Meeting:     session.Meeting.ToResponse(),
+		AccessToken: session.Token,
+		ExpiresAt:   session.ExpiresAt.Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meet-app/backend/pkg/auth"
+)
+
+type KeysHandler struct {
+	keys *auth.Keyring
+}
+
+func NewKeysHandler(keys *auth.Keyring) *KeysHandler {
+	return &KeysHandler{
+		keys: keys,
+	}
+}
+
+// JWKS godoc
+// @Summary Token verification keys
+// @Description Public keys that verify access tokens, selected by the token's kid header. Empty when tokens are signed with a shared HS256 secret.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} auth.JSONWebKeySet
+// @Router /.well-known/jwks.json [get]
+func (h *KeysHandler) JWKS(c *gin.Context) {
+	// Short enough for verifiers to pick up a rotated key quickly
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
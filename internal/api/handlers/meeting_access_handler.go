@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/internal/service"
+)
+
+type SetPasscodeRequest struct {
+	Passcode string `json:"passcode"` // Empty removes the passcode
+}
+
+type CreateInviteRequest struct {
+	ExpiresInHours    int  `json:"expires_in_hours"` // Defaults to the server setting
+	MaxUses           int  `json:"max_uses"`         // 0 means unlimited
+	BypassWaitingRoom bool `json:"bypass_waiting_room"`
+}
+
+// SetMeetingPasscode godoc
+// @Summary Set or remove the meeting passcode
+// @Description Require a passcode from first-time participants, or remove it with an empty passcode (host only)
+// @Tags meetings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param request body SetPasscodeRequest true "Passcode"
+// @Success 200 {object} map[string]bool
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/passcode [put]
+func (h *MeetingHandler) SetMeetingPasscode(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	var req SetPasscodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.meetingService.SetMeetingPasscode(meetingID, userID, req.Passcode); err != nil {
+		switch err {
+		case repository.ErrMeetingNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Meeting not found")
+		case service.ErrUnauthorizedAccess:
+			middleware.RespondWithError(c, http.StatusForbidden, "Only host can change the passcode")
+		case service.ErrInvalidPasscodeFormat:
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to update passcode")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"has_passcode": req.Passcode != ""})
+}
+
+// CreateInvite godoc
+// @Summary Create an invite link
+// @Description Create a signed invite link that lets people join without the passcode, optionally skipping the waiting room (host only)
+// @Tags meetings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param request body CreateInviteRequest false "Invite options"
+// @Success 201 {object} models.MeetingInviteResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/invites [post]
+func (h *MeetingHandler) CreateInvite(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	var req CreateInviteRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	invite, err := h.meetingService.CreateInvite(meetingID, userID, service.InviteOptions{
+		ExpiresIn:         time.Duration(req.ExpiresInHours) * time.Hour,
+		MaxUses:           req.MaxUses,
+		BypassWaitingRoom: req.BypassWaitingRoom,
+	})
+	if err != nil {
+		switch err {
+		case repository.ErrMeetingNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Meeting not found")
+		case service.ErrUnauthorizedAccess:
+			middleware.RespondWithError(c, http.StatusForbidden, "Only host can create invites")
+		case service.ErrMeetingEnded:
+			middleware.RespondWithError(c, http.StatusConflict, "Meeting has ended")
+		case service.ErrInvalidInviteOptions:
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid invite options")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to create invite")
+		}
+		return
+	}
+
+	meeting, err := h.meetingService.GetMeetingByID(meetingID)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to create invite")
+		return
+	}
+
+	response, err := h.inviteResponse(meeting, invite)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to create invite")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListInvites godoc
+// @Summary List invite links
+// @Description List the invite links of a meeting with their usage (host only)
+// @Tags meetings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Success 200 {array} models.MeetingInviteResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/invites [get]
+func (h *MeetingHandler) ListInvites(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	invites, err := h.meetingService.ListInvites(meetingID, userID)
+	if err != nil {
+		switch err {
+		case repository.ErrMeetingNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Meeting not found")
+		case service.ErrUnauthorizedAccess:
+			middleware.RespondWithError(c, http.StatusForbidden, "Only host can view invites")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to get invites")
+		}
+		return
+	}
+
+	meeting, err := h.meetingService.GetMeetingByID(meetingID)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to get invites")
+		return
+	}
+
+	responses := make([]models.MeetingInviteResponse, len(invites))
+	for i := range invites {
+		if responses[i], err = h.inviteResponse(meeting, &invites[i]); err != nil {
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to get invites")
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// RevokeInvite godoc
+// @Summary Revoke an invite link
+// @Description Stop an invite link from admitting anyone (host only)
+// @Tags meetings
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param inviteId path string true "Invite ID"
+// @Success 204
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/invites/{inviteId} [delete]
+func (h *MeetingHandler) RevokeInvite(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	inviteID, err := uuid.Parse(c.Param("inviteId"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid invite ID")
+		return
+	}
+
+	if err := h.meetingService.RevokeInvite(meetingID, userID, inviteID); err != nil {
+		switch err {
+		case repository.ErrMeetingNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Meeting not found")
+		case repository.ErrInviteNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Invite not found")
+		case service.ErrUnauthorizedAccess:
+			middleware.RespondWithError(c, http.StatusForbidden, "Only host can revoke invites")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to revoke invite")
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// inviteResponse signs the invite and builds its response
+func (h *MeetingHandler) inviteResponse(meeting *models.Meeting, invite *models.MeetingInvite) (models.MeetingInviteResponse, error) {
+	token, link, err := h.meetingService.InviteLink(meeting, invite)
+	if err != nil {
+		return models.MeetingInviteResponse{}, err
+	}
+	return invite.ToResponse(token, link), nil
+}
+
+// respondJoinAccessError writes the response for passcode and invite
+// failures, reporting whether err was one of them
+func respondJoinAccessError(c *gin.Context, err error) bool {
+	var lockedErr *service.PasscodeLockedError
+	switch {
+	case errors.As(err, &lockedErr):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+		middleware.RespondWithError(c, http.StatusTooManyRequests, "Too many wrong passcodes, try again later")
+	case err == service.ErrPasscodeRequired:
+		middleware.RespondWithError(c, http.StatusForbidden, "Passcode required")
+	case err == service.ErrInvalidPasscode:
+		middleware.RespondWithError(c, http.StatusForbidden, "Invalid passcode")
+	case err == service.ErrInvalidInvite:
+		middleware.RespondWithError(c, http.StatusForbidden, "Invalid or expired invite link")
+	case err == service.ErrWaitingRoomRequired:
+		middleware.RespondWithError(c, http.StatusForbidden, "Ask to join through the waiting room")
+	default:
+		return false
+	}
+	return true
+}
@@ -1,16 +1,23 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/attendance"
 	"github.com/meet-app/backend/internal/models"
 	"github.com/meet-app/backend/internal/repository"
 	"github.com/meet-app/backend/internal/service"
 	"github.com/meet-app/backend/internal/sse"
+	"github.com/meet-app/backend/internal/transcript"
 )
 
 type MeetingHandler struct {
@@ -26,18 +33,34 @@ func NewMeetingHandler(meetingService service.MeetingService, messageService ser
 }
 
 type CreateMeetingRequest struct {
-	Title       string                  `json:"title" binding:"required"`
-	Description string                  `json:"description"`
-	Settings    models.MeetingSettings `json:"settings"`
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	Passcode    string `json:"passcode"` // Optional, 4-64 characters
+	// Settings default to the organization's defaults for organization meetings
+	Settings       *models.MeetingSettings  `json:"settings"`
+	OrganizationID *uuid.UUID               `json:"organization_id"`
+	Visibility     models.MeetingVisibility `json:"visibility"` // public (default) or organization
 }
 
 type JoinMeetingRequest struct {
-	Code string `json:"code" binding:"required"`
+	Code        string `json:"code" binding:"required"`
+	Passcode    string `json:"passcode"`
+	InviteToken string `json:"invite_token"`
 }
 
 type SendMessageRequest struct {
-	Content string `json:"content" binding:"required"`
-	Type    models.MessageType `json:"type"`
+	Content     string             `json:"content" binding:"required"`
+	Type        models.MessageType `json:"type"`
+	ParentID    *uuid.UUID         `json:"parent_id"`
+	RecipientID *uuid.UUID         `json:"recipient_id"` // Set for private direct messages
+}
+
+type UpdateRoleRequest struct {
+	Role models.ParticipantRole `json:"role" binding:"required"`
+}
+
+type ReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required"`
 }
 
 type UpdateMediaStatusRequest struct {
@@ -48,7 +71,7 @@ type UpdateMediaStatusRequest struct {
 
 // CreateMeeting godoc
 // @Summary Create a new meeting
-// @Description Create a new meeting with title, description, and settings
+// @Description Create a new meeting with title, description, settings and an optional passcode, optionally owned by one of the user's organizations
 // @Tags meetings
 // @Accept json
 // @Produce json
@@ -57,6 +80,8 @@ type UpdateMediaStatusRequest struct {
 // @Success 201 {object} models.MeetingResponse
 // @Failure 400 {object} middleware.ErrorResponse
 // @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /meetings [post]
 func (h *MeetingHandler) CreateMeeting(c *gin.Context) {
@@ -72,8 +97,26 @@ func (h *MeetingHandler) CreateMeeting(c *gin.Context) {
 		return
 	}
 
-	meeting, err := h.meetingService.CreateMeeting(userID, req.Title, req.Description, req.Settings)
+	meeting, err := h.meetingService.CreateMeeting(
+		userID, req.Title, req.Description, req.Passcode, req.Settings, req.OrganizationID, req.Visibility, clientInfo(c),
+	)
 	if err != nil {
+		if err == service.ErrInvalidPasscodeFormat || err == service.ErrInvalidVisibility {
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err == service.ErrEmailNotVerified {
+			middleware.RespondWithError(c, http.StatusForbidden, "Verify your email address to host meetings")
+			return
+		}
+		if err == service.ErrHostMFARequired {
+			middleware.RespondWithError(c, http.StatusForbidden, "Enable two-factor authentication to host meetings")
+			return
+		}
+		if err == repository.ErrOrganizationNotFound {
+			middleware.RespondWithError(c, http.StatusNotFound, "Organization not found")
+			return
+		}
 		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to create meeting")
 		return
 	}
@@ -81,9 +124,167 @@ func (h *MeetingHandler) CreateMeeting(c *gin.Context) {
 	c.JSON(http.StatusCreated, meeting.ToResponse())
 }
 
+// ListMeetings godoc
+// @Summary List the current user's meetings
+// @Description Meetings the current user hosted or attended, with participant counts and last activity. Scheduled meetings are listed soonest first, others newest first.
+// @Tags meetings
+// @Produce json
+// @Security BearerAuth
+// @Param role query string false "Only meetings the user hosted or attended (hosted, attended)"
+// @Param status query string false "Meeting status (scheduled, active, ended)"
+// @Param from query string false "Earliest meeting time (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Latest meeting time (RFC3339 or YYYY-MM-DD)"
+// @Param q query string false "Search meeting titles"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size" default(20)
+// @Success 200 {object} models.MeetingListResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings [get]
+func (h *MeetingHandler) ListMeetings(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	params, ok := meetingListParamsFromQuery(c, userID)
+	if !ok {
+		return
+	}
+
+	items, next, err := h.meetingService.ListUserMeetings(params)
+	if err != nil {
+		if err == service.ErrInvalidListFilter {
+			middleware.RespondWithError(c, http.StatusBadRequest, "role must be hosted or attended and status one of scheduled, active, ended")
+			return
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to list meetings")
+		return
+	}
+
+	respondMeetingList(c, items, next)
+}
+
+// ListOrganizationMeetings godoc
+// @Summary List an organization's meetings
+// @Description Meetings of an organization the current user belongs to, with the user's role in each. Scheduled meetings are listed soonest first, others newest first.
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param role query string false "Only meetings the user hosted or attended (hosted, attended)"
+// @Param status query string false "Meeting status (scheduled, active, ended)"
+// @Param from query string false "Earliest meeting time (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Latest meeting time (RFC3339 or YYYY-MM-DD)"
+// @Param q query string false "Search meeting titles"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size" default(20)
+// @Success 200 {object} models.MeetingListResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /organizations/{id}/meetings [get]
+func (h *MeetingHandler) ListOrganizationMeetings(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	orgID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	params, ok := meetingListParamsFromQuery(c, userID)
+	if !ok {
+		return
+	}
+
+	items, next, err := h.meetingService.ListOrganizationMeetings(orgID, params)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidListFilter:
+			middleware.RespondWithError(c, http.StatusBadRequest, "role must be hosted or attended and status one of scheduled, active, ended")
+		case repository.ErrOrganizationNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Organization not found")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to list meetings")
+		}
+		return
+	}
+
+	respondMeetingList(c, items, next)
+}
+
+// meetingListParamsFromQuery reads the meeting listing filters from the query
+// string, responding with 400 and false when one is malformed
+func meetingListParamsFromQuery(c *gin.Context, userID uuid.UUID) (repository.MeetingListParams, bool) {
+	params := repository.MeetingListParams{
+		UserID: userID,
+		Role:   repository.MeetingListRole(c.Query("role")),
+		Status: models.MeetingStatus(c.Query("status")),
+		Query:  c.Query("q"),
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := parseTimeParam(fromStr, false)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid from date")
+			return params, false
+		}
+		params.From = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := parseTimeParam(toStr, true)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid to date")
+			return params, false
+		}
+		params.To = &to
+	}
+
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		cursor, err := repository.DecodeMeetingCursor(cursorStr)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid cursor")
+			return params, false
+		}
+		params.Cursor = cursor
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			params.Limit = limit
+		}
+	}
+
+	return params, true
+}
+
+// respondMeetingList writes one page of a meeting listing
+func respondMeetingList(c *gin.Context, items []models.MeetingListItem, next *repository.MeetingCursor) {
+	response := models.MeetingListResponse{
+		Meetings: make([]models.MeetingListItemResponse, len(items)),
+	}
+	for i := range items {
+		response.Meetings[i] = items[i].ToResponse()
+	}
+	if next != nil {
+		response.NextCursor = next.Encode()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // GetMeetingByCode godoc
 // @Summary Get meeting by code
-// @Description Get meeting details by meeting code
+// @Description Get meeting details by meeting code (case and dashes are ignored)
 // @Tags meetings
 // @Produce json
 // @Param code path string true "Meeting code"
@@ -109,7 +310,7 @@ func (h *MeetingHandler) GetMeetingByCode(c *gin.Context) {
 
 // JoinMeeting godoc
 // @Summary Join a meeting
-// @Description Join an existing meeting by code
+// @Description Join an existing meeting by code. First-time participants of a passcode-protected meeting need the passcode or an invite token. Users the waiting room applies to (non-members of organization-only meetings, holders of invites that do not skip it) get 403 and must send a join-request over /ws.
 // @Tags meetings
 // @Accept json
 // @Produce json
@@ -118,7 +319,11 @@ func (h *MeetingHandler) GetMeetingByCode(c *gin.Context) {
 // @Success 200 {object} models.ParticipantResponse
 // @Failure 400 {object} middleware.ErrorResponse
 // @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
 // @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 410 {object} middleware.ErrorResponse
+// @Failure 429 {object} middleware.ErrorResponse
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /meetings/join [post]
 func (h *MeetingHandler) JoinMeeting(c *gin.Context) {
@@ -146,8 +351,14 @@ func (h *MeetingHandler) JoinMeeting(c *gin.Context) {
 	}
 
 	// Join meeting as guest
-	participant, err := h.meetingService.JoinMeeting(userID, meeting.ID, models.ParticipantRoleGuest)
+	participant, err := h.meetingService.JoinMeeting(userID, meeting.ID, models.ParticipantRoleGuest, service.JoinCredentials{
+		Passcode:    req.Passcode,
+		InviteToken: req.InviteToken,
+	})
 	if err != nil {
+		if respondJoinAccessError(c, err) {
+			return
+		}
 		if err == service.ErrMeetingFull {
 			middleware.RespondWithError(c, http.StatusConflict, "Meeting is full")
 			return
@@ -156,6 +367,10 @@ func (h *MeetingHandler) JoinMeeting(c *gin.Context) {
 			middleware.RespondWithError(c, http.StatusConflict, "Already in meeting")
 			return
 		}
+		if err == service.ErrMeetingEnded {
+			middleware.RespondWithError(c, http.StatusGone, "Meeting has ended")
+			return
+		}
 		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to join meeting")
 		return
 	}
@@ -167,10 +382,59 @@ func (h *MeetingHandler) JoinMeeting(c *gin.Context) {
 		Type: sse.EventParticipantJoined,
 		Data: participant.ToResponse(),
 	})
+	h.postSystemMessage(meeting.ID, userID, models.SystemMessageParticipantJoined, map[string]string{
+		"name": participant.User.Name,
+	})
 
 	c.JSON(http.StatusOK, participant.ToResponse())
 }
 
+// RegenerateMeetingCode godoc
+// @Summary Regenerate meeting code
+// @Description Replace the meeting code so links with the old code stop working (host only)
+// @Tags meetings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Success 200 {object} models.MeetingResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/regenerate-code [post]
+func (h *MeetingHandler) RegenerateMeetingCode(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	meeting, err := h.meetingService.RegenerateMeetingCode(meetingID, userID)
+	if err != nil {
+		switch err {
+		case repository.ErrMeetingNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Meeting not found")
+		case service.ErrUnauthorizedAccess:
+			middleware.RespondWithError(c, http.StatusForbidden, "Only host can regenerate the meeting code")
+		case service.ErrMeetingEnded:
+			middleware.RespondWithError(c, http.StatusConflict, "Meeting has ended")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to regenerate meeting code")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, meeting.ToResponse())
+}
+
 // LeaveMeeting godoc
 // @Summary Leave a meeting
 // @Description Leave the current meeting
@@ -179,6 +443,7 @@ func (h *MeetingHandler) JoinMeeting(c *gin.Context) {
 // @Param id path string true "Meeting ID"
 // @Success 200 {object} map[string]string
 // @Failure 401 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /meetings/{id}/leave [post]
 func (h *MeetingHandler) LeaveMeeting(c *gin.Context) {
@@ -196,6 +461,10 @@ func (h *MeetingHandler) LeaveMeeting(c *gin.Context) {
 	}
 
 	if err := h.meetingService.LeaveMeeting(userID, meetingID); err != nil {
+		if err == repository.ErrParticipantNotFound || err == service.ErrNotInMeeting {
+			middleware.RespondWithError(c, http.StatusConflict, "Not in meeting")
+			return
+		}
 		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to leave meeting")
 		return
 	}
@@ -206,13 +475,14 @@ func (h *MeetingHandler) LeaveMeeting(c *gin.Context) {
 		Type: sse.EventParticipantLeft,
 		Data: map[string]string{"user_id": userID.String()},
 	})
+	h.postSystemMessage(meetingID, userID, models.SystemMessageParticipantLeft, nil)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Left meeting successfully"})
 }
 
 // GetMeetingParticipants godoc
 // @Summary Get meeting participants
-// @Description Get all active participants in a meeting
+// @Description Get all active participants in a meeting, including their total time in the meeting across rejoins
 // @Tags meetings
 // @Produce json
 // @Security BearerAuth
@@ -245,6 +515,137 @@ func (h *MeetingHandler) GetMeetingParticipants(c *gin.Context) {
 	c.JSON(http.StatusOK, participantResponses)
 }
 
+// UpdateParticipantRole godoc
+// @Summary Change a participant's role
+// @Description Promote a participant to moderator or demote them to guest (host only)
+// @Tags meetings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param userId path string true "User ID"
+// @Param request body UpdateRoleRequest true "Update role request"
+// @Success 200 {object} models.ParticipantResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/participants/{userId}/role [patch]
+func (h *MeetingHandler) UpdateParticipantRole(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	targetUserID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	participant, err := h.meetingService.UpdateParticipantRole(meetingID, userID, targetUserID, req.Role)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidRole:
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		case service.ErrUnauthorizedAccess:
+			middleware.RespondWithError(c, http.StatusForbidden, "Only host can change roles")
+		case repository.ErrMeetingNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Meeting not found")
+		case repository.ErrParticipantNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Participant not found")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to update role")
+		}
+		return
+	}
+
+	sse.GetHub().BroadcastToMeeting(meetingID, sse.Event{
+		Type: sse.EventParticipantUpdated,
+		Data: participant.ToResponse(),
+	})
+
+	key := models.SystemMessageParticipantDemoted
+	if participant.Role == models.ParticipantRoleModerator {
+		key = models.SystemMessageParticipantPromoted
+	}
+	h.postSystemMessage(meetingID, targetUserID, key, map[string]string{
+		"name": participant.User.Name,
+		"role": string(participant.Role),
+	})
+
+	c.JSON(http.StatusOK, participant.ToResponse())
+}
+
+// UpdateMeetingSettings godoc
+// @Summary Update meeting settings
+// @Description Change the settings present in the request; others keep their value (host only)
+// @Tags meetings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param request body models.MeetingSettingsPatch true "Settings to change"
+// @Success 200 {object} models.MeetingSettings
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/settings [patch]
+func (h *MeetingHandler) UpdateMeetingSettings(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	var patch models.MeetingSettingsPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if patch.MaxDurationMinutes != nil && *patch.MaxDurationMinutes < 0 {
+		middleware.RespondWithError(c, http.StatusBadRequest, "max_duration_minutes must not be negative")
+		return
+	}
+
+	settings, err := h.meetingService.UpdateMeetingSettings(meetingID, userID, patch, clientInfo(c))
+	if err != nil {
+		switch err {
+		case service.ErrUnauthorizedAccess:
+			middleware.RespondWithError(c, http.StatusForbidden, "Only host can update settings")
+		case repository.ErrMeetingNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Meeting not found")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to update settings")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
 // SendMessage godoc
 // @Summary Send a chat message
 // @Description Send a chat message in a meeting
@@ -284,12 +685,36 @@ func (h *MeetingHandler) SendMessage(c *gin.Context) {
 		req.Type = models.MessageTypeText
 	}
 
-	message, err := h.messageService.SendMessage(userID, meetingID, req.Type, req.Content)
+	// System messages are only written by the server
+	if req.Type == models.MessageTypeSystem {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Cannot send system messages")
+		return
+	}
+
+	message, err := h.messageService.SendMessage(userID, meetingID, req.Type, req.Content, req.ParentID, req.RecipientID)
 	if err != nil {
 		if err == service.ErrUnauthorizedAccess {
 			middleware.RespondWithError(c, http.StatusUnauthorized, "Not in meeting")
 			return
 		}
+		if err == service.ErrInvalidParentMessage || err == service.ErrInvalidRecipient {
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err == service.ErrPrivateChatDisabled {
+			middleware.RespondWithError(c, http.StatusForbidden, err.Error())
+			return
+		}
+		if err == service.ErrMessageTooLong || err == service.ErrMessageRejected {
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		var rateLimitErr *service.RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(rateLimitErr.RetryAfter.Seconds()))))
+			middleware.RespondWithError(c, http.StatusTooManyRequests, "Too many messages, slow down")
+			return
+		}
 		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to send message")
 		return
 	}
@@ -299,7 +724,7 @@ func (h *MeetingHandler) SendMessage(c *gin.Context) {
 
 // GetMessages godoc
 // @Summary Get meeting messages
-// @Description Get chat messages from a meeting
+// @Description Get chat messages from a meeting, including private messages sent to or by the caller
 // @Tags meetings
 // @Produce json
 // @Security BearerAuth
@@ -311,6 +736,12 @@ func (h *MeetingHandler) SendMessage(c *gin.Context) {
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /meetings/{id}/messages [get]
 func (h *MeetingHandler) GetMessages(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
 	meetingIDStr := c.Param("id")
 	meetingID, err := uuid.Parse(meetingIDStr)
 	if err != nil {
@@ -320,12 +751,12 @@ func (h *MeetingHandler) GetMessages(c *gin.Context) {
 
 	limit := 50
 	if limitStr := c.Query("limit"); limitStr != "" {
-		if parsedLimit, err := uuid.Parse(limitStr); err == nil {
-			limit = int(parsedLimit.ID())
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
 		}
 	}
 
-	messages, err := h.messageService.GetMeetingMessages(meetingID, limit)
+	messages, err := h.messageService.GetMeetingMessages(meetingID, userID, limit)
 	if err != nil {
 		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to get messages")
 		return
@@ -340,9 +771,441 @@ func (h *MeetingHandler) GetMessages(c *gin.Context) {
 	c.JSON(http.StatusOK, messageResponses)
 }
 
+// GetFlaggedMessages godoc
+// @Summary Get flagged messages
+// @Description Get chat messages flagged by moderation (host and moderators only)
+// @Tags meetings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Success 200 {array} models.FlaggedMessageResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/messages/flagged [get]
+func (h *MeetingHandler) GetFlaggedMessages(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	messages, err := h.messageService.GetFlaggedMessages(meetingID, userID)
+	if err != nil {
+		if err == service.ErrUnauthorizedAccess {
+			middleware.RespondWithError(c, http.StatusForbidden, "Only host or moderators can view flagged messages")
+			return
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to get flagged messages")
+		return
+	}
+
+	flaggedResponses := make([]models.FlaggedMessageResponse, len(messages))
+	for i, m := range messages {
+		flaggedResponses[i] = m.ToFlaggedResponse()
+	}
+
+	c.JSON(http.StatusOK, flaggedResponses)
+}
+
+// GetAttendance godoc
+// @Summary Get meeting attendance report
+// @Description Per-participant attendance (sessions, total time, role, media state) and meeting-level stats (host or moderators only)
+// @Tags meetings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Success 200 {object} attendance.Report
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/attendance [get]
+func (h *MeetingHandler) GetAttendance(c *gin.Context) {
+	report, ok := h.attendanceReport(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportAttendance godoc
+// @Summary Export meeting attendance report
+// @Description Download the attendance report as CSV (host or moderators only)
+// @Tags meetings
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param tz query string false "IANA time zone for timestamps" default(UTC)
+// @Success 200 {file} file
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/attendance/export [get]
+func (h *MeetingHandler) ExportAttendance(c *gin.Context) {
+	loc, err := time.LoadLocation(c.DefaultQuery("tz", "UTC"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid time zone")
+		return
+	}
+
+	report, ok := h.attendanceReport(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="meeting-%s-attendance.csv"`, report.Code))
+	c.Status(http.StatusOK)
+
+	if err := attendance.WriteCSV(c.Writer, report, loc); err != nil {
+		log.Printf("ExportAttendance: Failed to write report for meeting %s: %v", report.MeetingID, err)
+	}
+}
+
+// attendanceReport loads the attendance report for the meeting in the path,
+// writing an error response and returning false on failure
+func (h *MeetingHandler) attendanceReport(c *gin.Context) (*attendance.Report, bool) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return nil, false
+	}
+
+	report, err := h.meetingService.GetAttendanceReport(meetingID, userID)
+	if err != nil {
+		if err == service.ErrUnauthorizedAccess {
+			middleware.RespondWithError(c, http.StatusForbidden, "Only the host or moderators can view attendance")
+			return nil, false
+		}
+		if err == repository.ErrMeetingNotFound {
+			middleware.RespondWithError(c, http.StatusNotFound, "Meeting not found")
+			return nil, false
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to get attendance")
+		return nil, false
+	}
+
+	return report, true
+}
+
+// ExportMessages godoc
+// @Summary Export chat transcript
+// @Description Download the full chat history of a meeting the user participated in
+// @Tags meetings
+// @Produce plain
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param format query string false "Export format (md, json, csv, txt)" default(md)
+// @Param tz query string false "IANA time zone for timestamps" default(UTC)
+// @Success 200 {file} file
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/messages/export [get]
+func (h *MeetingHandler) ExportMessages(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	format := transcript.Format(c.DefaultQuery("format", string(transcript.FormatMarkdown)))
+
+	loc, err := time.LoadLocation(c.DefaultQuery("tz", "UTC"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid time zone")
+		return
+	}
+
+	writer, err := transcript.NewWriter(format, c.Writer, loc)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Format must be one of md, json, csv, txt")
+		return
+	}
+
+	meeting, err := h.messageService.GetTranscriptMeeting(meetingID, userID)
+	if err != nil {
+		if err == service.ErrUnauthorizedAccess {
+			middleware.RespondWithError(c, http.StatusForbidden, "Only participants can export the chat")
+			return
+		}
+		if err == repository.ErrMeetingNotFound {
+			middleware.RespondWithError(c, http.StatusNotFound, "Meeting not found")
+			return
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to export messages")
+		return
+	}
+
+	c.Header("Content-Type", transcript.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="meeting-%s-chat.%s"`, meeting.Code, format))
+	c.Status(http.StatusOK)
+
+	// From here on the response is streaming, so errors can only be logged
+	if err := writer.Begin(meeting); err != nil {
+		log.Printf("ExportMessages: Failed to write header for meeting %s: %v", meetingID, err)
+		return
+	}
+
+	err = h.messageService.StreamTranscript(meetingID, userID, func(messages []models.Message) error {
+		for i := range messages {
+			if err := writer.Write(&messages[i]); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		log.Printf("ExportMessages: Failed to stream messages for meeting %s: %v", meetingID, err)
+		return
+	}
+
+	if err := writer.End(); err != nil {
+		log.Printf("ExportMessages: Failed to finish transcript for meeting %s: %v", meetingID, err)
+	}
+}
+
+// GetReplies godoc
+// @Summary Get message replies
+// @Description Get the replies in a chat message thread
+// @Tags meetings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param messageId path string true "Message ID"
+// @Success 200 {array} models.MessageResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/messages/{messageId}/replies [get]
+func (h *MeetingHandler) GetReplies(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	messageID, err := uuid.Parse(c.Param("messageId"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid message ID")
+		return
+	}
+
+	replies, err := h.messageService.GetReplies(meetingID, messageID, userID)
+	if err != nil {
+		if err == repository.ErrMessageNotFound {
+			middleware.RespondWithError(c, http.StatusNotFound, "Message not found")
+			return
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to get replies")
+		return
+	}
+
+	replyResponses := make([]models.MessageResponse, len(replies))
+	for i, m := range replies {
+		replyResponses[i] = m.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, replyResponses)
+}
+
+// AddReaction godoc
+// @Summary React to a message
+// @Description Add an emoji reaction to a chat message
+// @Tags meetings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param messageId path string true "Message ID"
+// @Param request body ReactionRequest true "Reaction request"
+// @Success 201 {object} models.MessageResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/messages/{messageId}/reactions [post]
+func (h *MeetingHandler) AddReaction(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	messageID, err := uuid.Parse(c.Param("messageId"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid message ID")
+		return
+	}
+
+	var req ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	message, err := h.messageService.AddReaction(userID, meetingID, messageID, req.Emoji)
+	if err != nil {
+		h.respondReactionError(c, err, "Failed to add reaction")
+		return
+	}
+
+	c.JSON(http.StatusCreated, message.ToResponse())
+}
+
+// DeleteMessage godoc
+// @Summary Delete a chat message
+// @Description Delete a chat message. Authors may delete their own messages; the host and moderators may delete anyone's.
+// @Tags meetings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param messageId path string true "Message ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/messages/{messageId} [delete]
+func (h *MeetingHandler) DeleteMessage(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	messageID, err := uuid.Parse(c.Param("messageId"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid message ID")
+		return
+	}
+
+	if err := h.messageService.DeleteMessage(meetingID, messageID, userID, clientInfo(c)); err != nil {
+		switch err {
+		case service.ErrUnauthorizedAccess:
+			middleware.RespondWithError(c, http.StatusForbidden, "Only the author, host or moderators can delete messages")
+		case repository.ErrMessageNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Message not found")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to delete message")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Message deleted successfully"})
+}
+
+// RemoveReaction godoc
+// @Summary Remove a message reaction
+// @Description Remove the current user's emoji reaction from a chat message
+// @Tags meetings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param messageId path string true "Message ID"
+// @Param emoji path string true "Emoji"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /meetings/{id}/messages/{messageId}/reactions/{emoji} [delete]
+func (h *MeetingHandler) RemoveReaction(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	meetingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+		return
+	}
+
+	messageID, err := uuid.Parse(c.Param("messageId"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid message ID")
+		return
+	}
+
+	message, err := h.messageService.RemoveReaction(userID, meetingID, messageID, c.Param("emoji"))
+	if err != nil {
+		h.respondReactionError(c, err, "Failed to remove reaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, message.ToResponse())
+}
+
+// respondReactionError maps reaction errors to HTTP responses
+func (h *MeetingHandler) respondReactionError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case service.ErrInvalidReaction:
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+	case service.ErrUnauthorizedAccess:
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Not in meeting")
+	case repository.ErrMessageNotFound:
+		middleware.RespondWithError(c, http.StatusNotFound, "Message not found")
+	case repository.ErrReactionNotFound:
+		middleware.RespondWithError(c, http.StatusNotFound, "Reaction not found")
+	case repository.ErrReactionAlreadyExists:
+		middleware.RespondWithError(c, http.StatusConflict, "Reaction already exists")
+	default:
+		middleware.RespondWithError(c, http.StatusInternalServerError, fallback)
+	}
+}
+
 // EndMeeting godoc
 // @Summary End a meeting
-// @Description End a meeting (host only)
+// @Description End a meeting (host only). All participants are marked as left, WebSocket connections are closed with code 4000 and the meeting no longer accepts joins.
 // @Tags meetings
 // @Security BearerAuth
 // @Param id path string true "Meeting ID"
@@ -350,6 +1213,7 @@ func (h *MeetingHandler) GetMessages(c *gin.Context) {
 // @Failure 400 {object} middleware.ErrorResponse
 // @Failure 401 {object} middleware.ErrorResponse
 // @Failure 403 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /meetings/{id}/end [post]
 func (h *MeetingHandler) EndMeeting(c *gin.Context) {
@@ -366,15 +1230,21 @@ func (h *MeetingHandler) EndMeeting(c *gin.Context) {
 		return
 	}
 
-	if err := h.meetingService.EndMeeting(meetingID, userID); err != nil {
+	if err := h.meetingService.EndMeeting(meetingID, userID, clientInfo(c)); err != nil {
 		if err == service.ErrUnauthorizedAccess {
 			middleware.RespondWithError(c, http.StatusForbidden, "Only host can end meeting")
 			return
 		}
+		if err == service.ErrMeetingEnded {
+			middleware.RespondWithError(c, http.StatusConflict, "Meeting has already ended")
+			return
+		}
 		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to end meeting")
 		return
 	}
 
+	h.postSystemMessage(meetingID, userID, models.SystemMessageMeetingEnded, nil)
+
 	// Broadcast meeting ended event to all participants
 	hub := sse.GetHub()
 	hub.BroadcastToMeeting(meetingID, sse.Event{
@@ -384,3 +1254,15 @@ func (h *MeetingHandler) EndMeeting(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Meeting ended successfully"})
 }
+
+// postSystemMessage appends a system message to the meeting chat; failures are
+// logged because they must not fail the action that triggered them
+func (h *MeetingHandler) postSystemMessage(
+	meetingID, actorID uuid.UUID,
+	key models.SystemMessageKey,
+	params map[string]string,
+) {
+	if _, err := h.messageService.SendSystemMessage(meetingID, actorID, key, params); err != nil {
+		log.Printf("Failed to post system message %s to meeting %s: %v", key, meetingID, err)
+	}
+}
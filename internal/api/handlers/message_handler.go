@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/internal/service"
+)
+
+type MessageHandler struct {
+	messageService service.MessageService
+}
+
+func NewMessageHandler(messageService service.MessageService) *MessageHandler {
+	return &MessageHandler{
+		messageService: messageService,
+	}
+}
+
+// SearchMessages godoc
+// @Summary Search chat history
+// @Description Full-text search across messages in every meeting the current user participated in
+// @Tags messages
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search query"
+// @Param meeting_id query string false "Only search this meeting"
+// @Param user_id query string false "Only messages by this author"
+// @Param from query string false "Earliest message time (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Latest message time (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Maximum number of results" default(20)
+// @Param offset query int false "Number of results to skip" default(0)
+// @Success 200 {array} models.MessageSearchResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /messages/search [get]
+func (h *MessageHandler) SearchMessages(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	params := repository.MessageSearchParams{
+		Query:    c.Query("q"),
+		ViewerID: userID,
+	}
+
+	if meetingIDStr := c.Query("meeting_id"); meetingIDStr != "" {
+		meetingID, err := uuid.Parse(meetingIDStr)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid meeting ID")
+			return
+		}
+		params.MeetingID = &meetingID
+	}
+
+	if authorIDStr := c.Query("user_id"); authorIDStr != "" {
+		authorID, err := uuid.Parse(authorIDStr)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+		params.AuthorID = &authorID
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := parseTimeParam(fromStr, false)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid from date")
+			return
+		}
+		params.From = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := parseTimeParam(toStr, true)
+		if err != nil {
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid to date")
+			return
+		}
+		params.To = &to
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			params.Limit = limit
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil {
+			params.Offset = offset
+		}
+	}
+
+	results, err := h.messageService.SearchMessages(params)
+	if err != nil {
+		if err == service.ErrEmptySearchQuery {
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to search messages")
+		return
+	}
+
+	// Convert to response format
+	resultResponses := make([]models.MessageSearchResponse, len(results))
+	for i, r := range results {
+		resultResponses[i] = r.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, resultResponses)
+}
+
+// parseTimeParam parses an RFC3339 timestamp or a YYYY-MM-DD date. Plain dates
+// used as an upper bound cover the whole day.
+func parseTimeParam(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/service"
+)
+
+type MFAHandler struct {
+	mfaService service.MFAService
+}
+
+func NewMFAHandler(mfaService service.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"` // TOTP or, where accepted, recovery code
+}
+
+type MFAStatusResponse struct {
+	Enabled                bool    `json:"enabled"`
+	EnabledAt              *string `json:"enabled_at"`
+	RecoveryCodesRemaining int64   `json:"recovery_codes_remaining"`
+}
+
+type TOTPSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth:// URI to render as a QR code
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// GetStatus godoc
+// @Summary Get two-factor status
+// @Description Whether two-factor authentication is enabled for the current user and how many recovery codes are left
+// @Tags mfa
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} MFAStatusResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /users/me/mfa [get]
+func (h *MFAHandler) GetStatus(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	status, err := h.mfaService.Status(userID)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to get two-factor status")
+		return
+	}
+
+	response := MFAStatusResponse{
+		Enabled:                status.Enabled,
+		RecoveryCodesRemaining: status.RecoveryCodesRemaining,
+	}
+	if status.EnabledAt != nil {
+		enabledAt := status.EnabledAt.Format("2006-01-02T15:04:05Z07:00")
+		response.EnabledAt = &enabledAt
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// BeginTOTPSetup godoc
+// @Summary Start authenticator setup
+// @Description Generate a new TOTP secret and its provisioning URI. Two-factor authentication is enabled once a code from it is confirmed.
+// @Tags mfa
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} TOTPSetupResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /users/me/mfa/totp [post]
+func (h *MFAHandler) BeginTOTPSetup(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	setup, err := h.mfaService.BeginTOTPSetup(userID)
+	if err != nil {
+		if err == service.ErrMFAAlreadyEnabled {
+			middleware.RespondWithError(c, http.StatusConflict, err.Error())
+			return
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to start two-factor setup")
+		return
+	}
+
+	c.JSON(http.StatusOK, TOTPSetupResponse{
+		Secret:          setup.Secret,
+		ProvisioningURI: setup.ProvisioningURI,
+	})
+}
+
+// ConfirmTOTPSetup godoc
+// @Summary Confirm authenticator setup
+// @Description Enable two-factor authentication with a code from the new authenticator. Returns recovery codes, which are not shown again.
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "TOTP code"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /users/me/mfa/totp/confirm [post]
+func (h *MFAHandler) ConfirmTOTPSetup(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	codes, err := h.mfaService.ConfirmTOTPSetup(userID, req.Code)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidMFACode, service.ErrMFASetupNotStarted:
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		case service.ErrMFAAlreadyEnabled:
+			middleware.RespondWithError(c, http.StatusConflict, err.Error())
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to enable two-factor authentication")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTOTP godoc
+// @Summary Disable two-factor authentication
+// @Description Turn two-factor authentication off with a TOTP or recovery code. Remaining recovery codes are deleted.
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "TOTP or recovery code"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /users/me/mfa/totp/disable [post]
+func (h *MFAHandler) DisableTOTP(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.mfaService.DisableTOTP(userID, req.Code); err != nil {
+		switch err {
+		case service.ErrInvalidMFACode:
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		case service.ErrMFANotEnabled:
+			middleware.RespondWithError(c, http.StatusConflict, err.Error())
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to disable two-factor authentication")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace all recovery codes after checking a TOTP or recovery code
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "TOTP or recovery code"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /users/me/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidMFACode:
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		case service.ErrMFANotEnabled:
+			middleware.RespondWithError(c, http.StatusConflict, err.Error())
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to regenerate recovery codes")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/internal/service"
+)
+
+type OrganizationHandler struct {
+	organizationService service.OrganizationService
+}
+
+func NewOrganizationHandler(organizationService service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService: organizationService,
+	}
+}
+
+type CreateOrganizationRequest struct {
+	Name            string                  `json:"name" binding:"required"`
+	DefaultSettings *models.MeetingSettings `json:"default_settings"`
+}
+
+type UpdateOrganizationRequest struct {
+	Name            *string                 `json:"name"`
+	DefaultSettings *models.MeetingSettings `json:"default_settings"`
+}
+
+type AddOrganizationMemberRequest struct {
+	Email string                  `json:"email" binding:"required,email"`
+	Role  models.OrganizationRole `json:"role"` // owner, admin or member (default)
+}
+
+type UpdateOrganizationMemberRequest struct {
+	Role models.OrganizationRole `json:"role" binding:"required"`
+}
+
+// CreateOrganization godoc
+// @Summary Create an organization
+// @Description Create an organization with the current user as its owner
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateOrganizationRequest true "Organization name and default meeting settings"
+// @Success 201 {object} models.OrganizationResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /organizations [post]
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	member, err := h.organizationService.CreateOrganization(userID, req.Name, req.DefaultSettings)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidOrganizationName:
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		case service.ErrGuestMembership:
+			middleware.RespondWithError(c, http.StatusForbidden, err.Error())
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to create organization")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, member.Organization.ToResponse(member.Role))
+}
+
+// ListOrganizations godoc
+// @Summary List the current user's organizations
+// @Description List the organizations the current user belongs to, with their role in each
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.OrganizationResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /organizations [get]
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	memberships, err := h.organizationService.ListOrganizations(userID)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to list organizations")
+		return
+	}
+
+	responses := make([]models.OrganizationResponse, len(memberships))
+	for i := range memberships {
+		responses[i] = memberships[i].Organization.ToResponse(memberships[i].Role)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetOrganization godoc
+// @Summary Get an organization
+// @Description Get an organization the current user belongs to
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Success 200 {object} models.OrganizationResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /organizations/{id} [get]
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	userID, orgID, ok := organizationRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.organizationService.GetOrganization(orgID, userID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to get organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, member.Organization.ToResponse(member.Role))
+}
+
+// UpdateOrganization godoc
+// @Summary Update an organization
+// @Description Change the name or default meeting settings of an organization; omitted fields are kept (owners and admins)
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param request body UpdateOrganizationRequest true "Organization fields"
+// @Success 200 {object} models.OrganizationResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /organizations/{id} [patch]
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	userID, orgID, ok := organizationRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	member, err := h.organizationService.UpdateOrganization(orgID, userID, service.OrganizationUpdate{
+		Name:            req.Name,
+		DefaultSettings: req.DefaultSettings,
+	})
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to update organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, member.Organization.ToResponse(member.Role))
+}
+
+// DeleteOrganization godoc
+// @Summary Delete an organization
+// @Description Delete an organization (owners only). Its meetings stay with their hosts and become public.
+// @Tags organizations
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Success 204
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /organizations/{id} [delete]
+func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
+	userID, orgID, ok := organizationRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.organizationService.DeleteOrganization(orgID, userID); err != nil {
+		respondOrganizationError(c, err, "Failed to delete organization")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListMembers godoc
+// @Summary List organization members
+// @Description List the members of an organization the current user belongs to
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Success 200 {array} models.OrganizationMemberResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /organizations/{id}/members [get]
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	userID, orgID, ok := organizationRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.organizationService.ListMembers(orgID, userID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to list members")
+		return
+	}
+
+	responses := make([]models.OrganizationMemberResponse, len(members))
+	for i := range members {
+		responses[i] = members[i].ToResponse()
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// AddMember godoc
+// @Summary Add an organization member
+// @Description Add a registered user to the organization by email. Admins can add members; only owners can add admins and owners.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param request body AddOrganizationMemberRequest true "Member email and role"
+// @Success 201 {object} models.OrganizationMemberResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /organizations/{id}/members [post]
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	userID, orgID, ok := organizationRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	var req AddOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	member, err := h.organizationService.AddMember(orgID, userID, req.Email, req.Role)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to add member")
+		return
+	}
+
+	c.JSON(http.StatusCreated, member.ToResponse())
+}
+
+// UpdateMemberRole godoc
+// @Summary Change an organization member's role
+// @Description Change the role of a member (owners only). The last owner cannot step down.
+// @Tags organizations
+// @Accept json
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param userId path string true "Member user ID"
+// @Param request body UpdateOrganizationMemberRequest true "New role"
+// @Success 204
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /organizations/{id}/members/{userId} [patch]
+func (h *OrganizationHandler) UpdateMemberRole(c *gin.Context) {
+	userID, orgID, ok := organizationRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	targetUserID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req UpdateOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.organizationService.UpdateMemberRole(orgID, userID, targetUserID, req.Role); err != nil {
+		respondOrganizationError(c, err, "Failed to update member role")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveMember godoc
+// @Summary Remove an organization member
+// @Description Remove a member from the organization, or leave it with your own user ID. Admins can remove members and owners anyone; the last owner cannot leave.
+// @Tags organizations
+// @Security BearerAuth
+// @Param id path string true "Organization ID"
+// @Param userId path string true "Member user ID"
+// @Success 204
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /organizations/{id}/members/{userId} [delete]
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	userID, orgID, ok := organizationRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	targetUserID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.organizationService.RemoveMember(orgID, userID, targetUserID); err != nil {
+		respondOrganizationError(c, err, "Failed to remove member")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// organizationRequestIDs reads the current user and the organization in the
+// path, responding with an error and false when either is missing
+func organizationRequestIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	orgID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid organization ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, orgID, true
+}
+
+// respondOrganizationError maps organization service errors to responses
+func respondOrganizationError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case repository.ErrOrganizationNotFound:
+		middleware.RespondWithError(c, http.StatusNotFound, "Organization not found")
+	case repository.ErrOrganizationMemberNotFound:
+		middleware.RespondWithError(c, http.StatusNotFound, "Member not found")
+	case repository.ErrUserNotFound:
+		middleware.RespondWithError(c, http.StatusNotFound, "User not found")
+	case repository.ErrOrganizationMemberExists, service.ErrLastOrganizationOwner:
+		middleware.RespondWithError(c, http.StatusConflict, err.Error())
+	case service.ErrInvalidOrganizationName, service.ErrInvalidOrganizationRole:
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+	case service.ErrUnauthorizedAccess, service.ErrGuestMembership:
+		middleware.RespondWithError(c, http.StatusForbidden, err.Error())
+	default:
+		middleware.RespondWithError(c, http.StatusInternalServerError, fallback)
+	}
+}
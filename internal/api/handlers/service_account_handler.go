@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/internal/service"
+)
+
+type ServiceAccountHandler struct {
+	serviceAccountService service.ServiceAccountService
+}
+
+func NewServiceAccountHandler(serviceAccountService service.ServiceAccountService) *ServiceAccountHandler {
+	return &ServiceAccountHandler{
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+type CreateServiceAccountRequest struct {
+	Name     string `json:"name" binding:"required,min=1"`
+	Username string `json:"username" binding:"required,min=3,max=30"`
+}
+
+// CreateServiceAccount godoc
+// @Summary Create a service account
+// @Description Create a bot user owned by the current user. It cannot log in and authenticates with API tokens its owner creates.
+// @Tags service-accounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateServiceAccountRequest true "Service account name and username"
+// @Success 201 {object} models.UserResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /service-accounts [post]
+func (h *ServiceAccountHandler) CreateServiceAccount(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CreateServiceAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	account, err := h.serviceAccountService.CreateServiceAccount(userID, req.Name, req.Username)
+	if err != nil {
+		switch err {
+		case repository.ErrUsernameAlreadyExists:
+			middleware.RespondWithError(c, http.StatusConflict, "Username already exists")
+		case service.ErrInvalidName, service.ErrInvalidUsername:
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to create service account")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, account.ToResponse())
+}
+
+// ListServiceAccounts godoc
+// @Summary List service accounts
+// @Description List the service accounts owned by the current user
+// @Tags service-accounts
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.UserResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /service-accounts [get]
+func (h *ServiceAccountHandler) ListServiceAccounts(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	accounts, err := h.serviceAccountService.ListServiceAccounts(userID)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to list service accounts")
+		return
+	}
+
+	responses := make([]models.UserResponse, len(accounts))
+	for i := range accounts {
+		responses[i] = accounts[i].ToResponse()
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// DeleteServiceAccount godoc
+// @Summary Delete a service account
+// @Description Delete a service account owned by the current user and revoke its tokens. Meetings it hosted are kept.
+// @Tags service-accounts
+// @Security BearerAuth
+// @Param id path string true "Service account ID"
+// @Success 204
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /service-accounts/{id} [delete]
+func (h *ServiceAccountHandler) DeleteServiceAccount(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, "Invalid service account ID")
+		return
+	}
+
+	if err := h.serviceAccountService.DeleteServiceAccount(userID, accountID); err != nil {
+		if err == service.ErrServiceAccountNotFound {
+			middleware.RespondWithError(c, http.StatusNotFound, "Service account not found")
+			return
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to delete service account")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
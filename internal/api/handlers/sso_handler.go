@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/service"
+	"github.com/meet-app/backend/pkg/auth"
+	"github.com/meet-app/backend/pkg/oidc"
+)
+
+type SSOHandler struct {
+	ssoService   service.SSOService
+	postLoginURL string
+}
+
+// NewSSOHandler creates the SSO handler. With a postLoginURL the callback
+// redirects there with the result in the URL fragment instead of answering
+// with JSON.
+func NewSSOHandler(ssoService service.SSOService, postLoginURL string) *SSOHandler {
+	return &SSOHandler{
+		ssoService:   ssoService,
+		postLoginURL: postLoginURL,
+	}
+}
+
+// BeginLogin godoc
+// @Summary Start single sign-on
+// @Description Redirect to the OpenID Connect provider to log in
+// @Tags auth
+// @Success 302
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 502 {object} middleware.ErrorResponse
+// @Router /auth/oidc/login [get]
+func (h *SSOHandler) BeginLogin(c *gin.Context) {
+	authURL, err := h.ssoService.BeginLogin()
+	if err != nil {
+		if err == service.ErrSSODisabled {
+			middleware.RespondWithError(c, http.StatusNotFound, "Single sign-on is not configured")
+			return
+		}
+		log.Printf("SSO: failed to start login: %v", err)
+		if errors.Is(err, oidc.ErrDiscovery) {
+			middleware.RespondWithError(c, http.StatusBadGateway, "Identity provider is unavailable")
+			return
+		}
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to start single sign-on")
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback godoc
+// @Summary Finish single sign-on
+// @Description Redeem the provider's authorization code and issue tokens. Users with two-factor authentication get a challenge to finish at /auth/login/mfa instead. Redirects to OIDC_POST_LOGIN_URL with the tokens, challenge or error in the fragment when it is set.
+// @Tags auth
+// @Produce json
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from the login redirect"
+// @Success 200 {object} AuthResponse
+// @Success 302
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 502 {object} middleware.ErrorResponse
+// @Router /auth/oidc/callback [get]
+func (h *SSOHandler) Callback(c *gin.Context) {
+	if providerErr := c.Query("error"); providerErr != "" {
+		h.fail(c, http.StatusUnauthorized, "Identity provider refused the login: "+providerErr)
+		return
+	}
+
+	code, state := c.Query("code"), c.Query("state")
+	if code == "" || state == "" {
+		h.fail(c, http.StatusBadRequest, "Missing code or state")
+		return
+	}
+
+	user, tokens, err := h.ssoService.CompleteLogin(code, state, clientInfo(c))
+	if err != nil {
+		var mfaErr *service.MFARequiredError
+		if errors.As(err, &mfaErr) {
+			h.requireMFA(c, mfaErr)
+			return
+		}
+		switch {
+		case err == service.ErrSSODisabled:
+			middleware.RespondWithError(c, http.StatusNotFound, "Single sign-on is not configured")
+		case err == service.ErrSSOInvalidState:
+			h.fail(c, http.StatusBadRequest, err.Error())
+		case err == service.ErrSSOEmailNotVerified, err == service.ErrSSONoAccount, err == service.ErrSSOAccountUnverified, err == service.ErrAccountSuspended:
+			h.fail(c, http.StatusForbidden, err.Error())
+		case errors.Is(err, oidc.ErrDiscovery):
+			log.Printf("SSO: %v", err)
+			h.fail(c, http.StatusBadGateway, "Identity provider is unavailable")
+		case errors.Is(err, oidc.ErrTokenExchange), errors.Is(err, oidc.ErrInvalidIDToken), errors.Is(err, oidc.ErrUnknownSigningKey):
+			log.Printf("SSO: %v", err)
+			h.fail(c, http.StatusUnauthorized, "Single sign-on failed")
+		default:
+			log.Printf("SSO: failed to complete login: %v", err)
+			h.fail(c, http.StatusInternalServerError, "Failed to complete single sign-on")
+		}
+		return
+	}
+
+	h.succeed(c, user, tokens)
+}
+
+func (h *SSOHandler) succeed(c *gin.Context, user *models.User, tokens *auth.TokenPair) {
+	expiresAt := tokens.ExpiresAt.Format("2006-01-02T15:04:05Z07:00")
+
+	if h.postLoginURL == "" {
+		c.JSON(http.StatusOK, AuthResponse{
+			User:         user.ToResponse(),
+			AccessToken:  tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			ExpiresAt:    expiresAt,
+		})
+		return
+	}
+
+	// The fragment keeps the tokens out of server logs and Referer headers
+	fragment := url.Values{
+		"access_token":  {tokens.AccessToken},
+		"refresh_token": {tokens.RefreshToken},
+		"expires_at":    {expiresAt},
+	}
+	c.Redirect(http.StatusFound, h.postLoginURL+"#"+fragment.Encode())
+}
+
+// requireMFA hands the client the challenge to finish at /auth/login/mfa
+func (h *SSOHandler) requireMFA(c *gin.Context, mfaErr *service.MFARequiredError) {
+	expiresAt := mfaErr.ExpiresAt.Format("2006-01-02T15:04:05Z07:00")
+
+	if h.postLoginURL == "" {
+		c.JSON(http.StatusOK, MFAChallengeResponse{
+			MFARequired:    true,
+			ChallengeToken: mfaErr.ChallengeToken,
+			ExpiresAt:      expiresAt,
+		})
+		return
+	}
+
+	fragment := url.Values{
+		"mfa_required":    {"true"},
+		"challenge_token": {mfaErr.ChallengeToken},
+		"expires_at":      {expiresAt},
+	}
+	c.Redirect(http.StatusFound, h.postLoginURL+"#"+fragment.Encode())
+}
+
+func (h *SSOHandler) fail(c *gin.Context, status int, message string) {
+	if h.postLoginURL == "" {
+		middleware.RespondWithError(c, status, message)
+		return
+	}
+
+	fragment := url.Values{"error": {message}}
+	c.Redirect(http.StatusFound, h.postLoginURL+"#"+fragment.Encode())
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/avatar"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/internal/service"
+	"github.com/meet-app/backend/pkg/storage"
+)
+
+type UserHandler struct {
+	userService service.UserService
+}
+
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+type UpdateProfileRequest struct {
+	Name      *string `json:"name"`
+	Username  *string `json:"username"`
+	AvatarURL *string `json:"avatar_url"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+}
+
+type AvatarUploadResponse struct {
+	UploadURL string `json:"upload_url"`
+	ObjectKey string `json:"object_key"`
+	ExpiresAt string `json:"expires_at"`
+	MaxBytes  int64  `json:"max_bytes"`
+}
+
+type CompleteAvatarUploadRequest struct {
+	ObjectKey string `json:"object_key" binding:"required"`
+}
+
+type AvatarResponse struct {
+	User       models.UserResponse `json:"user"`
+	AvatarURLs map[string]string   `json:"avatar_urls"` // Keyed by size in pixels
+}
+
+// UpdateProfile godoc
+// @Summary Update current user's profile
+// @Description Change the name, username or avatar URL of the current user; omitted fields are kept
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateProfileRequest true "Profile fields"
+// @Success 200 {object} models.UserResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /users/me [patch]
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(userID, service.ProfileUpdate{
+		Name:      req.Name,
+		Username:  req.Username,
+		AvatarURL: req.AvatarURL,
+	})
+	if err != nil {
+		switch err {
+		case repository.ErrUsernameAlreadyExists:
+			middleware.RespondWithError(c, http.StatusConflict, "Username already exists")
+		case service.ErrInvalidName, service.ErrInvalidUsername, service.ErrInvalidAvatarURL:
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to update profile")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToResponse())
+}
+
+// ChangePassword godoc
+// @Summary Change current user's password
+// @Description Replace the password after checking the current one. Every other session is signed out; the returned tokens replace the caller's.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} AuthResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /users/me/password [post]
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := h.userService.ChangePassword(userID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		switch err {
+		case service.ErrIncorrectPassword:
+			middleware.RespondWithError(c, http.StatusForbidden, "Current password is incorrect")
+		case service.ErrWeakPassword:
+			middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to change password")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":  tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_at":    tokens.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// CreateAvatarUpload godoc
+// @Summary Get an avatar upload URL
+// @Description Get a presigned URL to PUT the original avatar image (JPEG, PNG or GIF) to, then call POST /users/me/avatar with the object key
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} AvatarUploadResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /users/me/avatar/upload-url [post]
+func (h *UserHandler) CreateAvatarUpload(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	upload, err := h.userService.CreateAvatarUpload(userID)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to create avatar upload")
+		return
+	}
+
+	c.JSON(http.StatusOK, AvatarUploadResponse{
+		UploadURL: upload.UploadURL,
+		ObjectKey: upload.ObjectKey,
+		ExpiresAt: upload.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		MaxBytes:  upload.MaxBytes,
+	})
+}
+
+// CompleteAvatarUpload godoc
+// @Summary Set an uploaded avatar
+// @Description Resize an uploaded avatar into square thumbnails and make it the current user's avatar
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CompleteAvatarUploadRequest true "Uploaded object"
+// @Success 200 {object} AvatarResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 413 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /users/me/avatar [post]
+func (h *UserHandler) CompleteAvatarUpload(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		middleware.RespondWithError(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CompleteAvatarUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, urls, err := h.userService.CompleteAvatarUpload(userID, req.ObjectKey)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidAvatarUpload:
+			middleware.RespondWithError(c, http.StatusBadRequest, "Invalid avatar upload")
+		case storage.ErrObjectNotFound:
+			middleware.RespondWithError(c, http.StatusNotFound, "Avatar upload not found")
+		case storage.ErrObjectTooLarge, avatar.ErrImageTooLarge:
+			middleware.RespondWithError(c, http.StatusRequestEntityTooLarge, "Avatar image is too large")
+		case avatar.ErrUnsupportedImage:
+			middleware.RespondWithError(c, http.StatusBadRequest, "Avatar must be a JPEG, PNG or GIF image")
+		default:
+			middleware.RespondWithError(c, http.StatusInternalServerError, "Failed to process avatar")
+		}
+		return
+	}
+
+	avatarURLs := make(map[string]string, len(urls))
+	for size, url := range urls {
+		avatarURLs[strconv.Itoa(size)] = url
+	}
+
+	c.JSON(http.StatusOK, AvatarResponse{
+		User:       user.ToResponse(),
+		AvatarURLs: avatarURLs,
+	})
+}
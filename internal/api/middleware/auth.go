@@ -6,62 +6,236 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/meet-app/backend/internal/config"
+	"github.com/meet-app/backend/internal/models"
 	"github.com/meet-app/backend/pkg/auth"
 )
 
-// AuthMiddleware validates JWT tokens and adds user info to context
-func AuthMiddleware(cfg *config.JWTConfig) gin.HandlerFunc {
+// APITokenVerifier resolves personal API tokens, returning auth.ErrInvalidToken
+// or auth.ErrExpiredToken for tokens that cannot be used
+type APITokenVerifier interface {
+	VerifyAPIToken(token string) (*models.APIToken, error)
+}
+
+// AuthMiddleware validates JWTs and personal API tokens and adds user info
+// to context. Guest tokens are rejected; see MeetingAuthMiddleware. Routes
+// restrict API tokens with RequireScope or RequireSession.
+func AuthMiddleware(keys *auth.Keyring, revocations *auth.RevocationStore, apiTokens APITokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := authenticate(c, keys, revocations, apiTokens)
+		if !ok {
+			return
+		}
+
+		if claims.Guest {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Guest access is limited to the meeting",
+			})
+			c.Abort()
+			return
+		}
+
+		setClaims(c, claims)
+		c.Next()
+	}
+}
+
+// MeetingAuthMiddleware is AuthMiddleware for the real-time endpoints of a
+// meeting, which also accept guest tokens for that meeting. The meeting is
+// taken from the :id path parameter or the meeting_id query parameter.
+func MeetingAuthMiddleware(keys *auth.Keyring, revocations *auth.RevocationStore, apiTokens APITokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var tokenString string
-
-		// Try to get token from Authorization header first
-		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			// Extract token from "Bearer <token>" format
-			parts := strings.SplitN(authHeader, " ", 2)
-			if len(parts) == 2 && parts[0] == "Bearer" {
-				tokenString = parts[1]
+		claims, ok := authenticate(c, keys, revocations, apiTokens)
+		if !ok {
+			return
+		}
+
+		if claims.Guest {
+			meetingID := c.Param("id")
+			if meetingID == "" {
+				meetingID = c.Query("meeting_id")
+			}
+
+			if claims.MeetingID == nil || meetingID != claims.MeetingID.String() {
+				c.JSON(http.StatusForbidden, gin.H{
+					"error": "Guest access is limited to the meeting",
+				})
+				c.Abort()
+				return
 			}
 		}
 
-		// Fallback to query parameter (for SSE/EventSource which can't send headers)
-		if tokenString == "" {
-			tokenString = c.Query("token")
+		setClaims(c, claims)
+		c.Next()
+	}
+}
+
+// authenticate validates the request token, aborting the request if it is
+// missing, invalid or revoked
+func authenticate(c *gin.Context, keys *auth.Keyring, revocations *auth.RevocationStore, apiTokens APITokenVerifier) (*auth.Claims, bool) {
+	var tokenString string
+
+	// Try to get token from Authorization header first
+	authHeader := c.GetHeader("Authorization")
+	if authHeader != "" {
+		// Extract token from "Bearer <token>" format
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			tokenString = parts[1]
+		}
+	}
+
+	// Fallback to query parameter (for SSE/EventSource which can't send headers)
+	if tokenString == "" {
+		tokenString = c.Query("token")
+	}
+
+	// If still no token, return error
+	if tokenString == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authorization token is required",
+		})
+		c.Abort()
+		return nil, false
+	}
+
+	if strings.HasPrefix(tokenString, auth.APITokenPrefix) && apiTokens != nil {
+		return authenticateAPIToken(c, apiTokens, tokenString)
+	}
+
+	// Validate token
+	claims, err := auth.ValidateToken(tokenString, keys)
+	if err != nil {
+		if err == auth.ErrExpiredToken {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token has expired",
+			})
+		} else {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid token",
+			})
 		}
+		c.Abort()
+		return nil, false
+	}
 
-		// If still no token, return error
-		if tokenString == "" {
+	// Tokens issued before e.g. a password change are no longer valid
+	if revocations != nil {
+		revoked, err := revocations.IsRevoked(c.Request.Context(), claims)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to validate token",
+			})
+			c.Abort()
+			return nil, false
+		}
+		if revoked {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Authorization token is required",
+				"error": "Token has been revoked",
 			})
 			c.Abort()
+			return nil, false
+		}
+	}
+
+	return claims, true
+}
+
+// authenticateAPIToken validates a personal API token and records its scopes
+// in the context for RequireScope
+func authenticateAPIToken(c *gin.Context, apiTokens APITokenVerifier, tokenString string) (*auth.Claims, bool) {
+	token, err := apiTokens.VerifyAPIToken(tokenString)
+	if err != nil {
+		switch err {
+		case auth.ErrExpiredToken:
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token has expired",
+			})
+		case auth.ErrInvalidToken:
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid token",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to validate token",
+			})
+		}
+		c.Abort()
+		return nil, false
+	}
+
+	c.Set("api_scopes", token.Scopes)
+	return &auth.Claims{
+		UserID:   token.UserID,
+		Email:    token.User.Email,
+		Username: token.User.Username,
+	}, true
+}
+
+// RequireScope rejects API tokens without the scope. Session tokens are
+// not scoped and always pass.
+func RequireScope(scope models.APIScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, isAPIToken := apiScopesFromContext(c)
+		if !isAPIToken {
+			c.Next()
 			return
 		}
 
-		// Validate token
-		claims, err := auth.ValidateToken(tokenString, cfg.Secret)
-		if err != nil {
-			if err == auth.ErrExpiredToken {
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"error": "Token has expired",
-				})
-			} else {
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"error": "Invalid token",
-				})
+		for _, s := range scopes {
+			if s == scope {
+				c.Next()
+				return
 			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Token is missing the " + string(scope) + " scope",
+		})
+		c.Abort()
+	}
+}
+
+// RequireSession rejects API tokens, e.g. for managing credentials
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsAPITokenFromContext(c) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "This endpoint cannot be used with an API token",
+			})
 			c.Abort()
 			return
 		}
+		c.Next()
+	}
+}
 
-		// Add user info to context
-		c.Set("user_id", claims.UserID)
-		c.Set("email", claims.Email)
-		c.Set("username", claims.Username)
+// IsAPITokenFromContext reports whether the request was made with a personal
+// API token
+func IsAPITokenFromContext(c *gin.Context) bool {
+	_, isAPIToken := apiScopesFromContext(c)
+	return isAPIToken
+}
 
-		c.Next()
+func apiScopesFromContext(c *gin.Context) ([]models.APIScope, bool) {
+	scopes, exists := c.Get("api_scopes")
+	if !exists {
+		return nil, false
 	}
+	s, _ := scopes.([]models.APIScope)
+	return s, true
+}
+
+// setClaims adds user info to context
+func setClaims(c *gin.Context, claims *auth.Claims) {
+	c.Set("user_id", claims.UserID)
+	c.Set("email", claims.Email)
+	c.Set("username", claims.Username)
+	c.Set("is_guest", claims.Guest)
+}
+
+// IsGuestFromContext reports whether the request was made with a guest token
+func IsGuestFromContext(c *gin.Context) bool {
+	return c.GetBool("is_guest")
 }
 
 // GetUserIDFromContext retrieves user ID from Gin context
@@ -60,6 +60,11 @@ func RequestIDMiddleware() gin.HandlerFunc {
 	}
 }
 
+// GetRequestIDFromContext returns the ID RequestIDMiddleware gave the request
+func GetRequestIDFromContext(c *gin.Context) string {
+	return c.GetString("request_id")
+}
+
 // generateRequestID generates a unique request ID
 func generateRequestID() string {
 	return time.Now().Format("20060102150405") + "-" + randomString(8)
@@ -0,0 +1,47 @@
+package attendance
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteCSV writes one row per attendee, with timestamps rendered in loc
+func WriteCSV(w io.Writer, report *Report, loc *time.Location) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write([]string{
+		"user_id", "name", "username", "email", "role",
+		"first_seen", "last_seen", "total_seconds", "sessions", "present",
+		"muted", "video_on", "sharing",
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range report.Attendees {
+		a := &report.Attendees[i]
+		err := cw.Write([]string{
+			a.UserID.String(),
+			a.Name,
+			a.Username,
+			a.Email,
+			string(a.Role),
+			a.FirstSeen.In(loc).Format(time.RFC3339),
+			a.LastSeen.In(loc).Format(time.RFC3339),
+			strconv.FormatInt(a.TotalSeconds, 10),
+			strconv.Itoa(len(a.Sessions)),
+			strconv.FormatBool(a.IsPresent),
+			strconv.FormatBool(a.IsMuted),
+			strconv.FormatBool(a.IsVideoOn),
+			strconv.FormatBool(a.IsSharing),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,179 @@
+package attendance
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+)
+
+// Report summarises who attended a meeting and for how long
+type Report struct {
+	MeetingID       uuid.UUID  `json:"meeting_id"`
+	Title           string     `json:"title"`
+	Code            string     `json:"code"`
+	StartedAt       *time.Time `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	UniqueAttendees int        `json:"unique_attendees"`
+	PeakConcurrency int        `json:"peak_concurrency"`
+	PeakAt          *time.Time `json:"peak_at"`
+	Attendees       []Attendee `json:"attendees"`
+	GeneratedAt     time.Time  `json:"generated_at"`
+}
+
+// Attendee is one participant's attendance within a meeting
+type Attendee struct {
+	UserID       uuid.UUID              `json:"user_id"`
+	Name         string                 `json:"name"`
+	Username     string                 `json:"username"`
+	Email        string                 `json:"email"`
+	Role         models.ParticipantRole `json:"role"`
+	FirstSeen    time.Time              `json:"first_seen"`
+	LastSeen     time.Time              `json:"last_seen"`
+	IsPresent    bool                   `json:"is_present"`
+	TotalSeconds int64                  `json:"total_seconds"`
+	Sessions     []Session              `json:"sessions"`
+	// Last known media state of the participant
+	IsMuted   bool `json:"is_muted"`
+	IsVideoOn bool `json:"is_video_on"`
+	IsSharing bool `json:"is_sharing"`
+}
+
+// Session is a single join/leave interval
+type Session struct {
+	JoinedAt time.Time  `json:"joined_at"`
+	LeftAt   *time.Time `json:"left_at"`
+	Seconds  int64      `json:"seconds"`
+}
+
+// BuildReport aggregates the participants of a meeting into a report. Open
+// sessions are counted up to the meeting end, or up to now if it is ongoing.
+func BuildReport(meeting *models.Meeting, participants []models.Participant, now time.Time) *Report {
+	cutoff := now
+	if meeting.EndedAt != nil && meeting.EndedAt.Before(now) {
+		cutoff = *meeting.EndedAt
+	}
+
+	report := &Report{
+		MeetingID:   meeting.ID,
+		Title:       meeting.Title,
+		Code:        meeting.Code,
+		StartedAt:   meeting.StartedAt,
+		EndedAt:     meeting.EndedAt,
+		Attendees:   make([]Attendee, 0, len(participants)),
+		GeneratedAt: now,
+	}
+	if meeting.StartedAt != nil && cutoff.After(*meeting.StartedAt) {
+		report.DurationSeconds = int64(cutoff.Sub(*meeting.StartedAt).Seconds())
+	}
+
+	var intervals []interval
+	for i := range participants {
+		attendee, sessions := buildAttendee(&participants[i], cutoff)
+		report.Attendees = append(report.Attendees, attendee)
+		intervals = append(intervals, sessions...)
+	}
+	report.UniqueAttendees = len(report.Attendees)
+	report.PeakConcurrency, report.PeakAt = peakConcurrency(intervals)
+
+	sort.SliceStable(report.Attendees, func(i, j int) bool {
+		return report.Attendees[i].FirstSeen.Before(report.Attendees[j].FirstSeen)
+	})
+
+	return report
+}
+
+type interval struct {
+	start, end time.Time
+}
+
+func buildAttendee(p *models.Participant, cutoff time.Time) (Attendee, []interval) {
+	attendee := Attendee{
+		UserID:    p.UserID,
+		Name:      p.User.Name,
+		Username:  p.User.Username,
+		Email:     p.User.Email,
+		Role:      p.Role,
+		IsPresent: p.LeftAt == nil,
+		IsMuted:   p.IsMuted,
+		IsVideoOn: p.IsVideoOn,
+		IsSharing: p.IsSharing,
+	}
+
+	// Participants from before sessions were recorded only have one interval
+	sessions := p.Sessions
+	if len(sessions) == 0 {
+		sessions = []models.ParticipantSession{{JoinedAt: p.JoinedAt, LeftAt: p.LeftAt}}
+	}
+
+	intervals := make([]interval, 0, len(sessions))
+	attendee.Sessions = make([]Session, 0, len(sessions))
+	for i := range sessions {
+		s := &sessions[i]
+		end := cutoff
+		if s.LeftAt != nil && s.LeftAt.Before(cutoff) {
+			end = *s.LeftAt
+		}
+		seconds := int64(0)
+		if end.After(s.JoinedAt) {
+			seconds = int64(end.Sub(s.JoinedAt).Seconds())
+		}
+
+		attendee.Sessions = append(attendee.Sessions, Session{
+			JoinedAt: s.JoinedAt,
+			LeftAt:   s.LeftAt,
+			Seconds:  seconds,
+		})
+		attendee.TotalSeconds += seconds
+		intervals = append(intervals, interval{start: s.JoinedAt, end: end})
+
+		if attendee.FirstSeen.IsZero() || s.JoinedAt.Before(attendee.FirstSeen) {
+			attendee.FirstSeen = s.JoinedAt
+		}
+		if end.After(attendee.LastSeen) {
+			attendee.LastSeen = end
+		}
+	}
+
+	return attendee, intervals
+}
+
+// peakConcurrency returns the highest number of overlapping intervals and
+// when it was first reached
+func peakConcurrency(intervals []interval) (int, *time.Time) {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+
+	events := make([]event, 0, len(intervals)*2)
+	for _, iv := range intervals {
+		if !iv.end.After(iv.start) {
+			continue
+		}
+		events = append(events, event{at: iv.start, delta: 1}, event{at: iv.end, delta: -1})
+	}
+
+	// Leaves sort before joins at the same instant so back-to-back sessions
+	// are not counted as overlapping
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].at.Equal(events[j].at) {
+			return events[i].delta < events[j].delta
+		}
+		return events[i].at.Before(events[j].at)
+	})
+
+	peak, current := 0, 0
+	var peakAt *time.Time
+	for i := range events {
+		current += events[i].delta
+		if current > peak {
+			peak = current
+			at := events[i].at
+			peakAt = &at
+		}
+	}
+	return peak, peakAt
+}
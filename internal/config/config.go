@@ -1,17 +1,34 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 )
 
+// DefaultJWTSecret is the development fallback for JWT_SECRET. It is public,
+// so Validate refuses it outside development.
+const DefaultJWTSecret = "your-super-secret-key-change-this"
+
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	MinIO    MinIOConfig
-	WebRTC   WebRTCConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	JWT       JWTConfig
+	MinIO     MinIOConfig
+	WebRTC    WebRTCConfig
+	Meeting   MeetingConfig
+	Chat      ChatConfig
+	Lifecycle LifecycleConfig
+	Mail      MailConfig
+	Account   AccountConfig
+	Login     LoginConfig
+	MFA       MFAConfig
+	OIDC      OIDCConfig
+	Audit     AuditConfig
+	Guest     GuestConfig
 }
 
 type ServerConfig struct {
@@ -37,17 +54,24 @@ type RedisConfig struct {
 }
 
 type JWTConfig struct {
-	Secret       string
-	ExpiryHours  int
-	RefreshHours int
+	Algorithm          string // HS256, RS256 or EdDSA
+	Secret             string // HS256 only
+	KeyDir             string // RS256/EdDSA private keys, shared between instances
+	KeyRotationDays    int    // 0 disables rotation
+	ExpiryHours        int
+	RefreshHours       int
+	GuestExpiryMinutes int
 }
 
 type MinIOConfig struct {
-	Endpoint  string
-	AccessKey string
-	SecretKey string
-	UseSSL    bool
-	Bucket    string
+	Endpoint     string
+	AccessKey    string
+	SecretKey    string
+	UseSSL       bool
+	Bucket       string
+	AvatarBucket string
+	Region       string
+	PublicURL    string // Base URL clients use to reach MinIO; defaults to Endpoint
 }
 
 type WebRTCConfig struct {
@@ -57,7 +81,111 @@ type WebRTCConfig struct {
 	TURNPass   string
 }
 
+type MeetingConfig struct {
+	CodeAlphabet           string
+	CodePattern            string
+	CodeMaxAttempts        int
+	RequireVerifiedHost    bool
+	RequireMFAHost         bool
+	PasscodeMaxAttempts    int
+	PasscodeLockoutMinutes int
+	InviteSecret           string
+	InviteDefaultHours     int
+	JoinURL                string
+}
+
+type MailConfig struct {
+	Driver       string // smtp, file or log
+	From         string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	FileDir      string
+}
+
+type AccountConfig struct {
+	VerificationTTLHours int
+	ResetTTLMinutes      int
+	VerifyEmailURL       string // Frontend page that receives ?token=
+	ResetPasswordURL     string // Frontend page that receives ?token=
+}
+
+// LoginConfig throttles password guessing. Failures per account and per IP
+// address back off exponentially; enough failures lock the account.
+type LoginConfig struct {
+	AccountFreeAttempts  int
+	IPFreeAttempts       int
+	BackoffBaseSeconds   int
+	BackoffMaxSeconds    int
+	FailureWindowMinutes int
+	LockoutThreshold     int
+	LockoutMinutes       int
+}
+
+// MFAConfig configures two-factor authentication with TOTP
+type MFAConfig struct {
+	Issuer               string // Shown next to the account in authenticator apps
+	ChallengeTTLMinutes  int
+	ChallengeMaxAttempts int
+}
+
+// OIDCConfig configures single sign-on through an OpenID Connect provider.
+// SSO is disabled while IssuerURL is empty.
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // This server's callback, /api/auth/oidc/callback
+	Scopes       []string
+	GroupsClaim  string
+	GroupRoles   []string // group=role pairs, e.g. meet-admins=admin
+	// GroupAdmins must be set for GroupRoles to grant the admin role, which
+	// opens the whole admin API to the provider's groups
+	GroupAdmins  bool
+	AllowSignup  bool   // Create accounts for unknown verified emails
+	PostLoginURL string // Frontend page that receives the tokens in its fragment
+}
+
+// Enabled reports whether an identity provider is configured
+func (c *OIDCConfig) Enabled() bool {
+	return c.IssuerURL != ""
+}
+
+type ChatConfig struct {
+	MaxMessageLength   int
+	RateLimitBurst     int
+	RateLimitPerMinute int
+	BlockedWords       []string
+	BlockedWordsAction string
+	ModerationWebhook  string
+	WebhookTimeoutMS   int
+}
+
+type GuestConfig struct {
+	// Guest joins allowed per IP address in a burst and per hour
+	JoinRateLimitBurst   int
+	JoinRateLimitPerHour int
+}
+
+type AuditConfig struct {
+	// RetentionDays is how long audit events are kept; 0 keeps them forever
+	RetentionDays int
+}
+
+type LifecycleConfig struct {
+	Enabled                 bool
+	IntervalSeconds         int
+	IdleTimeoutMinutes      int
+	MaxDurationMinutes      int
+	EndWarningMinutes       int
+	ParticipantGraceMinutes int
+	ScheduledExpiryHours    int
+}
+
 func Load() *Config {
+	jwtSecret := getEnv("JWT_SECRET", DefaultJWTSecret)
+
 	return &Config{
 		Server: ServerConfig{
 			Port:        getEnv("PORT", "8080"),
@@ -79,16 +207,23 @@ func Load() *Config {
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
 		JWT: JWTConfig{
-			Secret:       getEnv("JWT_SECRET", "your-super-secret-key-change-this"),
-			ExpiryHours:  getEnvAsInt("JWT_EXPIRY_HOURS", 24),
-			RefreshHours: getEnvAsInt("JWT_REFRESH_HOURS", 168),
+			Algorithm:          getEnv("JWT_ALGORITHM", "HS256"),
+			Secret:             jwtSecret,
+			KeyDir:             getEnv("JWT_KEY_DIR", "./keys"),
+			KeyRotationDays:    getEnvAsInt("JWT_KEY_ROTATION_DAYS", 30),
+			ExpiryHours:        getEnvAsInt("JWT_EXPIRY_HOURS", 24),
+			RefreshHours:       getEnvAsInt("JWT_REFRESH_HOURS", 168),
+			GuestExpiryMinutes: getEnvAsInt("JWT_GUEST_EXPIRY_MINUTES", 120),
 		},
 		MinIO: MinIOConfig{
-			Endpoint:  getEnv("MINIO_ENDPOINT", "localhost:9000"),
-			AccessKey: getEnv("MINIO_ACCESS_KEY", "minioadmin"),
-			SecretKey: getEnv("MINIO_SECRET_KEY", "minioadmin123"),
-			UseSSL:    getEnvAsBool("MINIO_USE_SSL", false),
-			Bucket:    getEnv("MINIO_BUCKET_NAME", "meeting-recordings"),
+			Endpoint:     getEnv("MINIO_ENDPOINT", "localhost:9000"),
+			AccessKey:    getEnv("MINIO_ACCESS_KEY", "minioadmin"),
+			SecretKey:    getEnv("MINIO_SECRET_KEY", "minioadmin123"),
+			UseSSL:       getEnvAsBool("MINIO_USE_SSL", false),
+			Bucket:       getEnv("MINIO_BUCKET_NAME", "meeting-recordings"),
+			AvatarBucket: getEnv("MINIO_AVATAR_BUCKET", "avatars"),
+			Region:       getEnv("MINIO_REGION", "us-east-1"),
+			PublicURL:    getEnv("MINIO_PUBLIC_URL", ""),
 		},
 		WebRTC: WebRTCConfig{
 			STUNServer: getEnv("STUN_SERVER", "stun:stun.l.google.com:19302"),
@@ -96,7 +231,119 @@ func Load() *Config {
 			TURNUser:   getEnv("TURN_USERNAME", ""),
 			TURNPass:   getEnv("TURN_PASSWORD", ""),
 		},
+		Meeting: MeetingConfig{
+			CodeAlphabet:           getEnv("MEETING_CODE_ALPHABET", "abcdefghjkmnpqrstuvwxyz"),
+			CodePattern:            getEnv("MEETING_CODE_PATTERN", "3-4-3"),
+			CodeMaxAttempts:        getEnvAsInt("MEETING_CODE_MAX_ATTEMPTS", 5),
+			RequireVerifiedHost:    getEnvAsBool("MEETING_REQUIRE_VERIFIED_HOST", false),
+			RequireMFAHost:         getEnvAsBool("MEETING_REQUIRE_MFA_HOST", false),
+			PasscodeMaxAttempts:    getEnvAsInt("MEETING_PASSCODE_MAX_ATTEMPTS", 5),
+			PasscodeLockoutMinutes: getEnvAsInt("MEETING_PASSCODE_LOCKOUT_MINUTES", 15),
+			InviteSecret:           getEnv("MEETING_INVITE_SECRET", jwtSecret),
+			InviteDefaultHours:     getEnvAsInt("MEETING_INVITE_DEFAULT_HOURS", 24),
+			JoinURL:                getEnv("MEETING_JOIN_URL", "http://localhost:8080/join"),
+		},
+		Chat: ChatConfig{
+			MaxMessageLength:   getEnvAsInt("CHAT_MAX_MESSAGE_LENGTH", 2000),
+			RateLimitBurst:     getEnvAsInt("CHAT_RATE_LIMIT_BURST", 10),
+			RateLimitPerMinute: getEnvAsInt("CHAT_RATE_LIMIT_PER_MINUTE", 30),
+			BlockedWords:       getEnvAsSlice("CHAT_BLOCKED_WORDS", nil),
+			BlockedWordsAction: getEnv("CHAT_BLOCKED_WORDS_ACTION", "mask"),
+			ModerationWebhook:  getEnv("CHAT_MODERATION_WEBHOOK_URL", ""),
+			WebhookTimeoutMS:   getEnvAsInt("CHAT_MODERATION_WEBHOOK_TIMEOUT_MS", 2000),
+		},
+		Lifecycle: LifecycleConfig{
+			Enabled:                 getEnvAsBool("MEETING_LIFECYCLE_ENABLED", true),
+			IntervalSeconds:         getEnvAsInt("MEETING_LIFECYCLE_INTERVAL_SECONDS", 30),
+			IdleTimeoutMinutes:      getEnvAsInt("MEETING_IDLE_TIMEOUT_MINUTES", 10),
+			MaxDurationMinutes:      getEnvAsInt("MEETING_MAX_DURATION_MINUTES", 0),
+			EndWarningMinutes:       getEnvAsInt("MEETING_END_WARNING_MINUTES", 5),
+			ParticipantGraceMinutes: getEnvAsInt("MEETING_PARTICIPANT_GRACE_MINUTES", 2),
+			ScheduledExpiryHours:    getEnvAsInt("MEETING_SCHEDULED_EXPIRY_HOURS", 24),
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "Meet App <no-reply@localhost>"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnvAsInt("SMTP_PORT", 587),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			FileDir:      getEnv("MAIL_FILE_DIR", "./tmp/mail"),
+		},
+		Account: AccountConfig{
+			VerificationTTLHours: getEnvAsInt("EMAIL_VERIFICATION_TTL_HOURS", 48),
+			ResetTTLMinutes:      getEnvAsInt("PASSWORD_RESET_TTL_MINUTES", 60),
+			VerifyEmailURL:       getEnv("VERIFY_EMAIL_URL", "http://localhost:8080/verify-email"),
+			ResetPasswordURL:     getEnv("RESET_PASSWORD_URL", "http://localhost:8080/reset-password"),
+		},
+		Login: LoginConfig{
+			AccountFreeAttempts:  getEnvAsInt("LOGIN_ACCOUNT_FREE_ATTEMPTS", 3),
+			IPFreeAttempts:       getEnvAsInt("LOGIN_IP_FREE_ATTEMPTS", 10),
+			BackoffBaseSeconds:   getEnvAsInt("LOGIN_BACKOFF_BASE_SECONDS", 1),
+			BackoffMaxSeconds:    getEnvAsInt("LOGIN_BACKOFF_MAX_SECONDS", 300),
+			FailureWindowMinutes: getEnvAsInt("LOGIN_FAILURE_WINDOW_MINUTES", 15),
+			LockoutThreshold:     getEnvAsInt("LOGIN_LOCKOUT_THRESHOLD", 10),
+			LockoutMinutes:       getEnvAsInt("LOGIN_LOCKOUT_MINUTES", 15),
+		},
+		MFA: MFAConfig{
+			Issuer:               getEnv("MFA_ISSUER", "Meet App"),
+			ChallengeTTLMinutes:  getEnvAsInt("MFA_CHALLENGE_TTL_MINUTES", 5),
+			ChallengeMaxAttempts: getEnvAsInt("MFA_CHALLENGE_MAX_ATTEMPTS", 5),
+		},
+		OIDC: OIDCConfig{
+			IssuerURL:    getEnv("OIDC_ISSUER_URL", ""),
+			ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:  getEnv("OIDC_REDIRECT_URL", "http://localhost:8080/api/auth/oidc/callback"),
+			Scopes:       getEnvAsSlice("OIDC_SCOPES", []string{"openid", "email", "profile"}),
+			GroupsClaim:  getEnv("OIDC_GROUPS_CLAIM", "groups"),
+			GroupRoles:   getEnvAsSlice("OIDC_GROUP_ROLES", nil),
+			GroupAdmins:  getEnvAsBool("OIDC_GROUP_ADMINS", false),
+			AllowSignup:  getEnvAsBool("OIDC_ALLOW_SIGNUP", true),
+			PostLoginURL: getEnv("OIDC_POST_LOGIN_URL", ""),
+		},
+		Guest: GuestConfig{
+			JoinRateLimitBurst:   getEnvAsInt("GUEST_JOIN_RATE_LIMIT_BURST", 5),
+			JoinRateLimitPerHour: getEnvAsInt("GUEST_JOIN_RATE_LIMIT_PER_HOUR", 20),
+		},
+		Audit: AuditConfig{
+			RetentionDays: getEnvAsInt("AUDIT_RETENTION_DAYS", 365),
+		},
+	}
+}
+
+// Validate rejects settings that are invalid, or unsafe for the environment
+func (c *Config) Validate() error {
+	// Token buckets need room for a request and a refill rate to wait for
+	rateLimits := []struct {
+		name  string
+		value int
+	}{
+		{"CHAT_RATE_LIMIT_BURST", c.Chat.RateLimitBurst},
+		{"CHAT_RATE_LIMIT_PER_MINUTE", c.Chat.RateLimitPerMinute},
+		{"GUEST_JOIN_RATE_LIMIT_BURST", c.Guest.JoinRateLimitBurst},
+		{"GUEST_JOIN_RATE_LIMIT_PER_HOUR", c.Guest.JoinRateLimitPerHour},
 	}
+	for _, limit := range rateLimits {
+		if limit.value <= 0 {
+			return fmt.Errorf("%s must be positive, got %d", limit.name, limit.value)
+		}
+	}
+
+	if c.Server.Environment == "development" {
+		return nil
+	}
+	if c.JWT.Algorithm == "HS256" && c.JWT.Secret == DefaultJWTSecret {
+		return errors.New("JWT_SECRET must be changed from the default outside development")
+	}
+	if c.Meeting.InviteSecret == DefaultJWTSecret {
+		return errors.New("MEETING_INVITE_SECRET (or JWT_SECRET) must be changed from the default outside development")
+	}
+	// The log and file drivers would leave reset links in logs or on disk
+	if c.Mail.Driver != "smtp" {
+		return fmt.Errorf("MAIL_DRIVER must be smtp outside development, got %q", c.Mail.Driver)
+	}
+	return nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -121,3 +368,18 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	var values []string
+	for _, v := range strings.Split(valueStr, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
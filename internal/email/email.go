@@ -0,0 +1,54 @@
+// Package email renders the transactional emails sent to users.
+package email
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+
+	"github.com/meet-app/backend/pkg/mail"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html"))
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt"))
+)
+
+// LinkData is the data of emails that carry a single action link
+type LinkData struct {
+	Name      string
+	Link      string
+	ExpiresIn string
+}
+
+// VerifyEmail renders the email asking a user to confirm their address
+func VerifyEmail(to string, data LinkData) (mail.Message, error) {
+	return render(to, "Confirm your email address", "verify_email", data)
+}
+
+// ResetPassword renders the email with a password reset link
+func ResetPassword(to string, data LinkData) (mail.Message, error) {
+	return render(to, "Reset your password", "reset_password", data)
+}
+
+// render executes the text and HTML templates called name
+func render(to, subject, name string, data interface{}) (mail.Message, error) {
+	var text, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return mail.Message{}, err
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return mail.Message{}, err
+	}
+
+	return mail.Message{
+		To:      to,
+		Subject: subject,
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,349 @@
+package lifecycle
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/config"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/service"
+	"github.com/meet-app/backend/internal/sse"
+	"github.com/meet-app/backend/pkg/redislock"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	lockKey           = "lifecycle:lock"
+	presenceKeyPrefix = "lifecycle:presence:"
+	emptyKeyPrefix    = "lifecycle:empty_since:"
+	warnedKeyPrefix   = "lifecycle:warned:"
+)
+
+// End reasons reported with the meeting_ended event
+const (
+	ReasonIdle        = "idle"
+	ReasonMaxDuration = "max_duration"
+)
+
+// Presence reports the users connected to this replica's signaling hub
+type Presence interface {
+	ConnectedUsers() map[uuid.UUID][]uuid.UUID
+}
+
+// Worker ends idle and overrunning meetings, closes dangling participants
+// and expires scheduled meetings that never started.
+//
+// Every replica publishes the users connected to its own hub to Redis on each
+// tick; the jobs themselves run on whichever replica holds the Redis lock, so
+// they see presence across all replicas.
+type Worker struct {
+	cfg            *config.LifecycleConfig
+	redis          *redis.Client
+	presence       Presence
+	meetingService service.MeetingService
+	messageService service.MessageService
+	interval       time.Duration
+}
+
+// NewWorker creates a lifecycle worker
+func NewWorker(
+	cfg *config.LifecycleConfig,
+	redisClient *redis.Client,
+	presence Presence,
+	meetingService service.MeetingService,
+	messageService service.MessageService,
+) *Worker {
+	interval := time.Duration(cfg.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	return &Worker{
+		cfg:            cfg,
+		redis:          redisClient,
+		presence:       presence,
+		meetingService: meetingService,
+		messageService: messageService,
+		interval:       interval,
+	}
+}
+
+// Run ticks until ctx is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Printf("Lifecycle: Worker started (interval %s)", w.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.tick(ctx)
+		}
+	}
+}
+
+func (w *Worker) tick(ctx context.Context) {
+	now := time.Now()
+
+	if err := w.reportPresence(ctx, now); err != nil {
+		log.Printf("Lifecycle: Failed to report presence: %v", err)
+	}
+
+	lock, err := redislock.Acquire(ctx, w.redis, lockKey, w.interval)
+	if err != nil {
+		log.Printf("Lifecycle: Failed to acquire lock: %v", err)
+		return
+	}
+	if lock == nil {
+		return // another replica is running the jobs
+	}
+	defer func() {
+		if err := lock.Release(ctx); err != nil {
+			log.Printf("Lifecycle: Failed to release lock: %v", err)
+		}
+	}()
+
+	w.expireScheduledMeetings(now)
+	w.checkActiveMeetings(ctx, now)
+	w.closeDanglingParticipants(ctx, now)
+}
+
+// reportPresence records the users connected to this replica. Entries older
+// than two intervals are treated as gone.
+func (w *Worker) reportPresence(ctx context.Context, now time.Time) error {
+	pipe := w.redis.Pipeline()
+	for meetingID, userIDs := range w.presence.ConnectedUsers() {
+		key := presenceKeyPrefix + meetingID.String()
+		members := make([]redis.Z, len(userIDs))
+		for i, userID := range userIDs {
+			members[i] = redis.Z{Score: float64(now.Unix()), Member: userID.String()}
+		}
+		pipe.ZAdd(ctx, key, members...)
+		pipe.Expire(ctx, key, 3*w.interval)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// connectedUsers returns the users recently seen connected to any replica
+func (w *Worker) connectedUsers(ctx context.Context, meetingID uuid.UUID, now time.Time) (map[uuid.UUID]bool, error) {
+	since := now.Add(-2 * w.interval).Unix()
+	members, err := w.redis.ZRangeByScore(ctx, presenceKeyPrefix+meetingID.String(), &redis.ZRangeBy{
+		Min: strconv.FormatInt(since, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[uuid.UUID]bool, len(members))
+	for _, member := range members {
+		if userID, err := uuid.Parse(member); err == nil {
+			users[userID] = true
+		}
+	}
+	return users, nil
+}
+
+func (w *Worker) expireScheduledMeetings(now time.Time) {
+	if w.cfg.ScheduledExpiryHours <= 0 {
+		return
+	}
+
+	before := now.Add(-time.Duration(w.cfg.ScheduledExpiryHours) * time.Hour)
+	expired, err := w.meetingService.ExpireScheduledMeetings(before)
+	if err != nil {
+		log.Printf("Lifecycle: Failed to expire scheduled meetings: %v", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("Lifecycle: Expired %d scheduled meetings that never started", expired)
+	}
+}
+
+func (w *Worker) checkActiveMeetings(ctx context.Context, now time.Time) {
+	meetings, err := w.meetingService.GetActiveMeetings()
+	if err != nil {
+		log.Printf("Lifecycle: Failed to load active meetings: %v", err)
+		return
+	}
+
+	for i := range meetings {
+		meeting := &meetings[i]
+
+		if w.checkMaxDuration(ctx, meeting, now) {
+			continue
+		}
+		w.checkIdle(ctx, meeting, now)
+	}
+}
+
+// checkMaxDuration warns before and ends the meeting at its maximum
+// duration. It reports whether the meeting was ended.
+func (w *Worker) checkMaxDuration(ctx context.Context, meeting *models.Meeting, now time.Time) bool {
+	limit := meeting.Settings.MaxDurationMinutes
+	if limit <= 0 {
+		limit = w.cfg.MaxDurationMinutes
+	}
+	if limit <= 0 || meeting.StartedAt == nil {
+		return false
+	}
+
+	endsAt := meeting.StartedAt.Add(time.Duration(limit) * time.Minute)
+	if !now.Before(endsAt) {
+		w.endMeeting(meeting, ReasonMaxDuration, models.SystemMessageMeetingEndedMaxTime)
+		return true
+	}
+
+	warning := time.Duration(w.cfg.EndWarningMinutes) * time.Minute
+	if warning <= 0 || now.Before(endsAt.Add(-warning)) {
+		return false
+	}
+
+	// Warn once per meeting, whichever replica runs the jobs
+	first, err := w.redis.SetNX(ctx, warnedKeyPrefix+meeting.ID.String(), 1, warning+2*w.interval).Result()
+	if err != nil || !first {
+		return false
+	}
+
+	minutesLeft := int(endsAt.Sub(now).Round(time.Minute) / time.Minute)
+	if minutesLeft < 1 {
+		minutesLeft = 1
+	}
+	sse.GetHub().BroadcastToMeeting(meeting.ID, sse.Event{
+		Type: sse.EventMeetingEndingSoon,
+		Data: map[string]interface{}{
+			"meeting_id":   meeting.ID.String(),
+			"ends_at":      endsAt,
+			"minutes_left": minutesLeft,
+		},
+	})
+	w.postSystemMessage(meeting, models.SystemMessageMeetingEndingSoon, map[string]string{
+		"minutes": strconv.Itoa(minutesLeft),
+	})
+	return false
+}
+
+// checkIdle ends the meeting once nobody has been connected to it for the
+// idle timeout
+func (w *Worker) checkIdle(ctx context.Context, meeting *models.Meeting, now time.Time) {
+	if w.cfg.IdleTimeoutMinutes <= 0 {
+		return
+	}
+
+	users, err := w.connectedUsers(ctx, meeting.ID, now)
+	if err != nil {
+		log.Printf("Lifecycle: Failed to read presence of meeting %s: %v", meeting.ID, err)
+		return
+	}
+
+	key := emptyKeyPrefix + meeting.ID.String()
+	if len(users) > 0 {
+		w.redis.Del(ctx, key)
+		return
+	}
+
+	idleTimeout := time.Duration(w.cfg.IdleTimeoutMinutes) * time.Minute
+	w.redis.SetNX(ctx, key, now.Unix(), idleTimeout+2*w.interval)
+
+	emptySince, err := w.redis.Get(ctx, key).Int64()
+	if err != nil {
+		log.Printf("Lifecycle: Failed to read idle state of meeting %s: %v", meeting.ID, err)
+		return
+	}
+	if now.Sub(time.Unix(emptySince, 0)) < idleTimeout {
+		return
+	}
+
+	w.redis.Del(ctx, key)
+	w.endMeeting(meeting, ReasonIdle, models.SystemMessageMeetingEndedIdle)
+}
+
+func (w *Worker) endMeeting(meeting *models.Meeting, reason string, key models.SystemMessageKey) {
+	if err := w.meetingService.AutoEndMeeting(meeting.ID, nil, reason, service.ClientInfo{}); err != nil {
+		if err == service.ErrMeetingEnded {
+			return // ended by the host in the meantime
+		}
+		log.Printf("Lifecycle: Failed to end meeting %s: %v", meeting.ID, err)
+		return
+	}
+	log.Printf("Lifecycle: Ended meeting %s (%s)", meeting.ID, reason)
+
+	w.postSystemMessage(meeting, key, nil)
+	sse.GetHub().BroadcastToMeeting(meeting.ID, sse.Event{
+		Type: sse.EventMeetingEnded,
+		Data: map[string]string{
+			"meeting_id": meeting.ID.String(),
+			"reason":     reason,
+		},
+	})
+}
+
+// closeDanglingParticipants marks participants as left when they are no
+// longer connected, and closes anyone still recorded in an ended meeting
+func (w *Worker) closeDanglingParticipants(ctx context.Context, now time.Time) {
+	if closed, err := w.meetingService.CloseParticipantsOfEndedMeetings(); err != nil {
+		log.Printf("Lifecycle: Failed to close participants of ended meetings: %v", err)
+	} else if closed > 0 {
+		log.Printf("Lifecycle: Closed %d participants of ended meetings", closed)
+	}
+
+	grace := time.Duration(w.cfg.ParticipantGraceMinutes) * time.Minute
+	if grace <= 0 {
+		return
+	}
+
+	participants, err := w.meetingService.FindDanglingParticipants(now.Add(-grace))
+	if err != nil {
+		log.Printf("Lifecycle: Failed to load dangling participants: %v", err)
+		return
+	}
+
+	connected := make(map[uuid.UUID]map[uuid.UUID]bool)
+	for i := range participants {
+		p := &participants[i]
+
+		users, ok := connected[p.MeetingID]
+		if !ok {
+			users, err = w.connectedUsers(ctx, p.MeetingID, now)
+			if err != nil {
+				log.Printf("Lifecycle: Failed to read presence of meeting %s: %v", p.MeetingID, err)
+				continue
+			}
+			connected[p.MeetingID] = users
+		}
+		if users[p.UserID] {
+			continue
+		}
+
+		if err := w.meetingService.LeaveMeeting(p.UserID, p.MeetingID); err != nil {
+			log.Printf("Lifecycle: Failed to close participant %s: %v", p.ID, err)
+			continue
+		}
+
+		sse.GetHub().BroadcastToMeeting(p.MeetingID, sse.Event{
+			Type: sse.EventParticipantLeft,
+			Data: map[string]string{"user_id": p.UserID.String()},
+		})
+		if _, err := w.messageService.SendSystemMessage(p.MeetingID, p.UserID, models.SystemMessageParticipantLeft, map[string]string{
+			"name": p.User.Name,
+		}); err != nil {
+			log.Printf("Lifecycle: Failed to post system message to meeting %s: %v", p.MeetingID, err)
+		}
+	}
+}
+
+// postSystemMessage posts an automatic message attributed to the host
+func (w *Worker) postSystemMessage(meeting *models.Meeting, key models.SystemMessageKey, params map[string]string) {
+	if params == nil {
+		params = make(map[string]string)
+	}
+	if _, err := w.messageService.SendSystemMessage(meeting.ID, meeting.HostID, key, params); err != nil {
+		log.Printf("Lifecycle: Failed to post system message %s to meeting %s: %v", key, meeting.ID, err)
+	}
+}
@@ -0,0 +1,83 @@
+package meetingcode
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultAlphabet leaves out letters that are easily confused when read
+// aloud or handwritten (i, l, o)
+const DefaultAlphabet = "abcdefghjkmnpqrstuvwxyz"
+
+// DefaultPattern gives codes like abc-defg-hij
+const DefaultPattern = "3-4-3"
+
+var ErrInvalidConfig = errors.New("meeting code alphabet needs at least two distinct characters and the pattern positive group sizes")
+
+// Generator produces random meeting codes made of dash-separated groups
+type Generator struct {
+	alphabet []rune
+	groups   []int
+}
+
+// NewGenerator creates a generator drawing from alphabet with group sizes
+// given as a dash-separated pattern such as "3-4-3"
+func NewGenerator(alphabet, pattern string) (*Generator, error) {
+	seen := make(map[rune]bool)
+	var runes []rune
+	for _, r := range strings.ToLower(alphabet) {
+		if r == '-' || seen[r] {
+			continue
+		}
+		seen[r] = true
+		runes = append(runes, r)
+	}
+	if len(runes) < 2 {
+		return nil, ErrInvalidConfig
+	}
+
+	var groups []int
+	for _, part := range strings.Split(pattern, "-") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n <= 0 {
+			return nil, ErrInvalidConfig
+		}
+		groups = append(groups, n)
+	}
+
+	return &Generator{alphabet: runes, groups: groups}, nil
+}
+
+// Generate returns a new random code
+func (g *Generator) Generate() (string, error) {
+	max := big.NewInt(int64(len(g.alphabet)))
+
+	var b strings.Builder
+	for i, size := range g.groups {
+		if i > 0 {
+			b.WriteByte('-')
+		}
+		for j := 0; j < size; j++ {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(g.alphabet[n.Int64()])
+		}
+	}
+	return b.String(), nil
+}
+
+// Normalize reduces a code to the form used for lookups: lower case with
+// dashes and whitespace removed, so "ABC-DEFG-HIJ" and "abcdefghij" match
+func Normalize(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIScope limits what a personal API token may do. Session tokens from
+// login are not scoped.
+type APIScope string
+
+const (
+	ScopeProfileRead   APIScope = "profile:read"
+	ScopeProfileWrite  APIScope = "profile:write"
+	ScopeMeetingsRead  APIScope = "meetings:read"
+	ScopeMeetingsWrite APIScope = "meetings:write"
+	ScopeMessagesRead  APIScope = "messages:read"
+	ScopeMessagesWrite APIScope = "messages:write"
+)
+
+// APIScopes lists every scope a token can be granted
+var APIScopes = []APIScope{
+	ScopeProfileRead,
+	ScopeProfileWrite,
+	ScopeMeetingsRead,
+	ScopeMeetingsWrite,
+	ScopeMessagesRead,
+	ScopeMessagesWrite,
+}
+
+// IsValid reports whether the scope is known
+func (s APIScope) IsValid() bool {
+	for _, scope := range APIScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIToken is a long-lived personal access token for automation. Only the
+// SHA-256 hash of the token is stored; Prefix identifies it in listings.
+type APIToken struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Prefix     string     `gorm:"size:16;not null" json:"prefix"`
+	TokenHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Scopes     []APIScope `gorm:"type:jsonb;serializer:json" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"` // nil never expires
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID
+func (t *APIToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for APIToken model
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
+
+// IsUsable reports whether the token is neither revoked nor expired
+func (t *APIToken) IsUsable(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// HasScope reports whether the token was granted the scope
+func (t *APIToken) HasScope(scope APIScope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APITokenResponse represents a token in API responses. Token is only set
+// when the token is created.
+type APITokenResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []APIScope `json:"scopes"`
+	Token      string     `json:"token,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ToResponse converts APIToken model to APITokenResponse. token is the
+// plaintext token, only available right after creation.
+func (t *APIToken) ToResponse(token string) APITokenResponse {
+	return APITokenResponse{
+		ID:         t.ID,
+		UserID:     t.UserID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     t.Scopes,
+		Token:      token,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
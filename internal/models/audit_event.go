@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditAction is the kind of action an audit event records
+type AuditAction string
+
+const (
+	AuditActionLogin                  AuditAction = "auth.login"
+	AuditActionLoginFailed            AuditAction = "auth.login_failed"
+	AuditActionRegister               AuditAction = "auth.register"
+	AuditActionMeetingCreated         AuditAction = "meeting.created"
+	AuditActionMeetingEnded           AuditAction = "meeting.ended"
+	AuditActionMeetingSettingsUpdated AuditAction = "meeting.settings_updated"
+	AuditActionJoinApproved           AuditAction = "join.approved"
+	AuditActionJoinRejected           AuditAction = "join.rejected"
+	AuditActionScreenShareStarted     AuditAction = "screen_share.started"
+	AuditActionScreenShareStopped     AuditAction = "screen_share.stopped"
+	AuditActionMessageDeleted         AuditAction = "message.deleted"
+	AuditActionUserSuspended          AuditAction = "admin.user_suspended"
+	AuditActionUserUnsuspended        AuditAction = "admin.user_unsuspended"
+	AuditActionUserDeleted            AuditAction = "admin.user_deleted"
+)
+
+// AuditEvent is an append-only record of a security- or moderation-relevant
+// action. ActorID is empty for actions the system took on its own, e.g.
+// ending an idle meeting.
+type AuditEvent struct {
+	ID           uuid.UUID              `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Action       AuditAction            `gorm:"type:varchar(50);not null;index" json:"action"`
+	ActorID      *uuid.UUID             `gorm:"type:uuid;index" json:"actor_id,omitempty"`
+	TargetUserID *uuid.UUID             `gorm:"type:uuid;index" json:"target_user_id,omitempty"`
+	MeetingID    *uuid.UUID             `gorm:"type:uuid;index" json:"meeting_id,omitempty"`
+	IPAddress    string                 `gorm:"size:45;not null;default:''" json:"ip_address,omitempty"`
+	RequestID    string                 `gorm:"size:64;not null;default:''" json:"request_id,omitempty"`
+	Payload      map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"payload,omitempty"`
+	CreatedAt    time.Time              `gorm:"index" json:"created_at"`
+}
+
+// BeforeCreate hook to generate UUID
+func (e *AuditEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for AuditEvent model
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MeetingInvite is a host-issued invite link. The link carries a signed
+// token; usage limits and revocation are tracked here.
+type MeetingInvite struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	MeetingID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"meeting_id"`
+	CreatedBy         uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	ExpiresAt         time.Time  `gorm:"not null" json:"expires_at"`
+	MaxUses           int        `gorm:"not null;default:0" json:"max_uses"` // 0 means unlimited
+	Uses              int        `gorm:"not null;default:0" json:"uses"`
+	BypassWaitingRoom bool       `gorm:"not null;default:false" json:"bypass_waiting_room"`
+	RevokedAt         *time.Time `json:"revoked_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// BeforeCreate hook to generate UUID
+func (i *MeetingInvite) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for MeetingInvite model
+func (MeetingInvite) TableName() string {
+	return "meeting_invites"
+}
+
+// IsUsable reports whether the invite can still admit someone at now
+func (i *MeetingInvite) IsUsable(now time.Time) bool {
+	if i.RevokedAt != nil || !now.Before(i.ExpiresAt) {
+		return false
+	}
+	return i.MaxUses == 0 || i.Uses < i.MaxUses
+}
+
+// MeetingInviteResponse represents an invite sent in API responses
+type MeetingInviteResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	MeetingID         uuid.UUID  `json:"meeting_id"`
+	Token             string     `json:"token"`
+	URL               string     `json:"url"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	MaxUses           int        `json:"max_uses"`
+	Uses              int        `json:"uses"`
+	BypassWaitingRoom bool       `json:"bypass_waiting_room"`
+	RevokedAt         *time.Time `json:"revoked_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// ToResponse converts MeetingInvite model to MeetingInviteResponse
+func (i *MeetingInvite) ToResponse(token, url string) MeetingInviteResponse {
+	return MeetingInviteResponse{
+		ID:                i.ID,
+		MeetingID:         i.MeetingID,
+		Token:             token,
+		URL:               url,
+		ExpiresAt:         i.ExpiresAt,
+		MaxUses:           i.MaxUses,
+		Uses:              i.Uses,
+		BypassWaitingRoom: i.BypassWaitingRoom,
+		RevokedAt:         i.RevokedAt,
+		CreatedAt:         i.CreatedAt,
+	}
+}
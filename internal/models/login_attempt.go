@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LoginMethod is how a user tried to log in
+type LoginMethod string
+
+const (
+	LoginMethodPassword LoginMethod = "password"
+	LoginMethodSSO      LoginMethod = "sso"
+)
+
+// LoginFailureReason explains why a login attempt was refused
+type LoginFailureReason string
+
+const (
+	LoginFailureInvalidCredentials LoginFailureReason = "invalid_credentials"
+	LoginFailureThrottled          LoginFailureReason = "throttled"
+	LoginFailureSSORejected        LoginFailureReason = "sso_rejected"
+	LoginFailureMFARequired        LoginFailureReason = "mfa_required" // Password accepted, second factor pending
+	LoginFailureInvalidMFACode     LoginFailureReason = "invalid_mfa_code"
+	LoginFailureSuspended          LoginFailureReason = "suspended"
+)
+
+// LoginAttempt is the audit record of a successful or failed login. UserID is
+// only set when the email belongs to an account.
+type LoginAttempt struct {
+	ID            uuid.UUID          `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID        *uuid.UUID         `gorm:"type:uuid;index" json:"user_id,omitempty"`
+	Email         string             `gorm:"size:255;not null;index" json:"email"`
+	IPAddress     string             `gorm:"size:45;not null" json:"ip_address"`
+	UserAgent     string             `gorm:"size:512;not null;default:''" json:"user_agent"`
+	Method        LoginMethod        `gorm:"type:varchar(20);not null;default:'password'" json:"method"`
+	Success       bool               `gorm:"not null" json:"success"`
+	FailureReason LoginFailureReason `gorm:"type:varchar(30);not null;default:''" json:"failure_reason,omitempty"`
+	CreatedAt     time.Time          `gorm:"index" json:"created_at"`
+}
+
+// BeforeCreate hook to generate UUID
+func (a *LoginAttempt) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for LoginAttempt model
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
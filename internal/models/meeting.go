@@ -1,9 +1,11 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/meetingcode"
 	"gorm.io/gorm"
 )
 
@@ -15,23 +17,43 @@ const (
 	MeetingStatusEnded     MeetingStatus = "ended"
 )
 
+// MeetingVisibility controls who gets into a meeting without waiting
+type MeetingVisibility string
+
+const (
+	// MeetingVisibilityPublic meetings admit whoever has the code and
+	// passcode through the waiting room
+	MeetingVisibilityPublic MeetingVisibility = "public"
+	// MeetingVisibilityOrganization meetings admit members of the meeting's
+	// organization straight away and send everyone else to the waiting room
+	MeetingVisibilityOrganization MeetingVisibility = "organization"
+)
+
+// IsValid reports whether the visibility is known
+func (v MeetingVisibility) IsValid() bool {
+	return v == MeetingVisibilityPublic || v == MeetingVisibilityOrganization
+}
+
 type Meeting struct {
-	ID           uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	Code         string          `gorm:"uniqueIndex;not null;size:36" json:"code"`
-	Title        string          `gorm:"not null" json:"title"`
-	Description  string          `gorm:"type:text" json:"description"`
-	HostID       uuid.UUID       `gorm:"type:uuid;not null" json:"host_id"`
-	Status       MeetingStatus   `gorm:"type:varchar(20);default:'scheduled'" json:"status"`
-	ScheduledAt  *time.Time      `json:"scheduled_at"`
-	StartedAt    *time.Time      `json:"started_at"`
-	EndedAt      *time.Time      `json:"ended_at"`
-	MaxUsers     int             `gorm:"default:50" json:"max_users"`
-	IsRecording  bool            `gorm:"default:false" json:"is_recording"`
-	RecordingURL string          `gorm:"type:text" json:"recording_url"`
-	Settings     MeetingSettings `gorm:"type:jsonb;serializer:json" json:"settings"`
-	CreatedAt    time.Time       `json:"created_at"`
-	UpdatedAt    time.Time       `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt  `gorm:"index" json:"-"`
+	ID             uuid.UUID         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Code           string            `gorm:"uniqueIndex;not null;size:36" json:"code"`
+	Title          string            `gorm:"not null" json:"title"`
+	Description    string            `gorm:"type:text" json:"description"`
+	HostID         uuid.UUID         `gorm:"type:uuid;not null" json:"host_id"`
+	OrganizationID *uuid.UUID        `gorm:"type:uuid;index" json:"organization_id"`
+	Visibility     MeetingVisibility `gorm:"type:varchar(20);not null;default:'public'" json:"visibility"`
+	Status         MeetingStatus     `gorm:"type:varchar(20);default:'scheduled'" json:"status"`
+	ScheduledAt    *time.Time        `json:"scheduled_at"`
+	StartedAt      *time.Time        `json:"started_at"`
+	EndedAt        *time.Time        `json:"ended_at"`
+	MaxUsers       int               `gorm:"default:50" json:"max_users"`
+	IsRecording    bool              `gorm:"default:false" json:"is_recording"`
+	RecordingURL   string            `gorm:"type:text" json:"recording_url"`
+	Settings       MeetingSettings   `gorm:"type:jsonb;serializer:json" json:"settings"`
+	PasscodeHash   string            `gorm:"size:255;not null;default:''" json:"-"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt    `gorm:"index" json:"-"`
 
 	// Relationships
 	Host         User          `gorm:"foreignKey:HostID" json:"host,omitempty"`
@@ -46,6 +68,57 @@ type MeetingSettings struct {
 	VideoOnJoin        bool `json:"video_on_join"`
 	WaitingRoomEnabled bool `json:"waiting_room_enabled"`
 	RecordingEnabled   bool `json:"recording_enabled"`
+	AllowPrivateChat   bool `json:"allow_private_chat"`
+	// AllowGuests lets people without an account join through the waiting room
+	AllowGuests bool `json:"allow_guests"`
+	// MaxDurationMinutes ends the meeting automatically after this long;
+	// zero falls back to the server default
+	MaxDurationMinutes int `json:"max_duration_minutes"`
+}
+
+// UnmarshalJSON defaults allow_private_chat to true when it is left out, as
+// it was added after the other settings and clients may not send it
+func (s *MeetingSettings) UnmarshalJSON(data []byte) error {
+	type plain MeetingSettings
+	decoded := plain{AllowPrivateChat: true}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*s = MeetingSettings(decoded)
+	return nil
+}
+
+// MeetingSettingsPatch changes only the settings that are set
+type MeetingSettingsPatch struct {
+	AllowChat          *bool `json:"allow_chat"`
+	AllowScreenShare   *bool `json:"allow_screen_share"`
+	MuteOnJoin         *bool `json:"mute_on_join"`
+	VideoOnJoin        *bool `json:"video_on_join"`
+	WaitingRoomEnabled *bool `json:"waiting_room_enabled"`
+	RecordingEnabled   *bool `json:"recording_enabled"`
+	AllowPrivateChat   *bool `json:"allow_private_chat"`
+	AllowGuests        *bool `json:"allow_guests"`
+	MaxDurationMinutes *int  `json:"max_duration_minutes"`
+}
+
+// Apply copies the set fields onto settings
+func (p *MeetingSettingsPatch) Apply(settings *MeetingSettings) {
+	setBool := func(dst *bool, src *bool) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setBool(&settings.AllowChat, p.AllowChat)
+	setBool(&settings.AllowScreenShare, p.AllowScreenShare)
+	setBool(&settings.MuteOnJoin, p.MuteOnJoin)
+	setBool(&settings.VideoOnJoin, p.VideoOnJoin)
+	setBool(&settings.WaitingRoomEnabled, p.WaitingRoomEnabled)
+	setBool(&settings.RecordingEnabled, p.RecordingEnabled)
+	setBool(&settings.AllowPrivateChat, p.AllowPrivateChat)
+	setBool(&settings.AllowGuests, p.AllowGuests)
+	if p.MaxDurationMinutes != nil {
+		settings.MaxDurationMinutes = *p.MaxDurationMinutes
+	}
 }
 
 // BeforeCreate hook to generate UUID and meeting code
@@ -54,9 +127,13 @@ func (m *Meeting) BeforeCreate(tx *gorm.DB) error {
 		m.ID = uuid.New()
 	}
 
-	// Generate unique meeting code using UUID
+	// Services assign a code from the configured generator; this is a fallback
 	if m.Code == "" {
-		m.Code = generateMeetingCode()
+		code, err := generateMeetingCode()
+		if err != nil {
+			return err
+		}
+		m.Code = code
 	}
 
 	// Set default settings if all fields are zero (not provided)
@@ -68,59 +145,137 @@ func (m *Meeting) BeforeCreate(tx *gorm.DB) error {
 			VideoOnJoin:        true,
 			WaitingRoomEnabled: false,
 			RecordingEnabled:   false,
+			AllowPrivateChat:   true,
 		}
 	}
 
 	return nil
 }
 
+// HasPasscode reports whether joining the meeting requires a passcode
+func (m *Meeting) HasPasscode() bool {
+	return m.PasscodeHash != ""
+}
+
+// IsOrganizationOnly reports whether only members of the meeting's
+// organization skip the waiting room
+func (m *Meeting) IsOrganizationOnly() bool {
+	return m.OrganizationID != nil && m.Visibility == MeetingVisibilityOrganization
+}
+
 // TableName specifies the table name for Meeting model
 func (Meeting) TableName() string {
 	return "meetings"
 }
 
-// generateMeetingCode generates a unique meeting code using UUID
-func generateMeetingCode() string {
-	// Generate a new UUID and return as string
-	return uuid.New().String()
+// defaultCodeGenerator produces codes with the default alphabet and pattern
+var defaultCodeGenerator, _ = meetingcode.NewGenerator(meetingcode.DefaultAlphabet, meetingcode.DefaultPattern)
+
+// generateMeetingCode generates a random abc-defg-hij style meeting code
+func generateMeetingCode() (string, error) {
+	return defaultCodeGenerator.Generate()
 }
 
 // MeetingResponse represents the meeting data sent in API responses
 type MeetingResponse struct {
-	ID           uuid.UUID       `json:"id"`
-	Code         string          `json:"code"`
-	Title        string          `json:"title"`
-	Description  string          `json:"description"`
-	HostID       uuid.UUID       `json:"host_id"`
-	Host         UserResponse    `json:"host"`
-	Status       MeetingStatus   `json:"status"`
-	ScheduledAt  *time.Time      `json:"scheduled_at"`
-	StartedAt    *time.Time      `json:"started_at"`
-	EndedAt      *time.Time      `json:"ended_at"`
-	MaxUsers     int             `json:"max_users"`
-	IsRecording  bool            `json:"is_recording"`
-	RecordingURL string          `json:"recording_url,omitempty"`
-	Settings     MeetingSettings `json:"settings"`
-	CreatedAt    time.Time       `json:"created_at"`
+	ID             uuid.UUID         `json:"id"`
+	Code           string            `json:"code"`
+	Title          string            `json:"title"`
+	Description    string            `json:"description"`
+	HostID         uuid.UUID         `json:"host_id"`
+	Host           UserResponse      `json:"host"`
+	OrganizationID *uuid.UUID        `json:"organization_id"`
+	Visibility     MeetingVisibility `json:"visibility"`
+	Status         MeetingStatus     `json:"status"`
+	ScheduledAt    *time.Time        `json:"scheduled_at"`
+	StartedAt      *time.Time        `json:"started_at"`
+	EndedAt        *time.Time        `json:"ended_at"`
+	MaxUsers       int               `json:"max_users"`
+	IsRecording    bool              `json:"is_recording"`
+	RecordingURL   string            `json:"recording_url,omitempty"`
+	Settings       MeetingSettings   `json:"settings"`
+	HasPasscode    bool              `json:"has_passcode"`
+	CreatedAt      time.Time         `json:"created_at"`
 }
 
 // ToResponse converts Meeting model to MeetingResponse
 func (m *Meeting) ToResponse() MeetingResponse {
 	return MeetingResponse{
-		ID:           m.ID,
-		Code:         m.Code,
-		Title:        m.Title,
-		Description:  m.Description,
-		HostID:       m.HostID,
-		Host:         m.Host.ToResponse(),
-		Status:       m.Status,
-		ScheduledAt:  m.ScheduledAt,
-		StartedAt:    m.StartedAt,
-		EndedAt:      m.EndedAt,
-		MaxUsers:     m.MaxUsers,
-		IsRecording:  m.IsRecording,
-		RecordingURL: m.RecordingURL,
-		Settings:     m.Settings,
-		CreatedAt:    m.CreatedAt,
+		ID:             m.ID,
+		Code:           m.Code,
+		Title:          m.Title,
+		Description:    m.Description,
+		HostID:         m.HostID,
+		Host:           m.Host.ToResponse(),
+		OrganizationID: m.OrganizationID,
+		Visibility:     m.Visibility,
+		Status:         m.Status,
+		ScheduledAt:    m.ScheduledAt,
+		StartedAt:      m.StartedAt,
+		EndedAt:        m.EndedAt,
+		MaxUsers:       m.MaxUsers,
+		IsRecording:    m.IsRecording,
+		RecordingURL:   m.RecordingURL,
+		Settings:       m.Settings,
+		HasPasscode:    m.HasPasscode(),
+		CreatedAt:      m.CreatedAt,
+	}
+}
+
+// MeetingListItem is a meeting in a user's meeting history together with
+// its participation stats
+type MeetingListItem struct {
+	Meeting                Meeting
+	ViewerRole             ParticipantRole
+	ParticipantCount       int64
+	ActiveParticipantCount int64
+	LastActivityAt         *time.Time
+}
+
+// MeetingListItemResponse represents a meeting history entry sent in API responses
+type MeetingListItemResponse struct {
+	MeetingResponse
+	Role                   ParticipantRole `json:"role"`
+	ParticipantCount       int64           `json:"participant_count"`
+	ActiveParticipantCount int64           `json:"active_participant_count"`
+	LastActivityAt         *time.Time      `json:"last_activity_at"`
+}
+
+// ToResponse converts MeetingListItem to MeetingListItemResponse
+func (i *MeetingListItem) ToResponse() MeetingListItemResponse {
+	return MeetingListItemResponse{
+		MeetingResponse:        i.Meeting.ToResponse(),
+		Role:                   i.ViewerRole,
+		ParticipantCount:       i.ParticipantCount,
+		ActiveParticipantCount: i.ActiveParticipantCount,
+		LastActivityAt:         i.LastActivityAt,
+	}
+}
+
+// MeetingListResponse is one page of a user's meeting history
+type MeetingListResponse struct {
+	Meetings   []MeetingListItemResponse `json:"meetings"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
+}
+
+// MeetingSummaryResponse is a compact view of a meeting embedded in other responses
+type MeetingSummaryResponse struct {
+	ID        uuid.UUID     `json:"id"`
+	Code      string        `json:"code"`
+	Title     string        `json:"title"`
+	Status    MeetingStatus `json:"status"`
+	StartedAt *time.Time    `json:"started_at"`
+	EndedAt   *time.Time    `json:"ended_at"`
+}
+
+// ToSummaryResponse converts Meeting model to MeetingSummaryResponse
+func (m *Meeting) ToSummaryResponse() MeetingSummaryResponse {
+	return MeetingSummaryResponse{
+		ID:        m.ID,
+		Code:      m.Code,
+		Title:     m.Title,
+		Status:    m.Status,
+		StartedAt: m.StartedAt,
+		EndedAt:   m.EndedAt,
 	}
 }
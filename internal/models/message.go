@@ -16,19 +16,27 @@ const (
 )
 
 type Message struct {
-	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	MeetingID uuid.UUID      `gorm:"type:uuid;not null;index" json:"meeting_id"`
-	UserID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
-	Type      MessageType    `gorm:"type:varchar(20);default:'text'" json:"type"`
-	Content   string         `gorm:"type:text;not null" json:"content"`
-	FileURL   string         `gorm:"type:text" json:"file_url,omitempty"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             uuid.UUID         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	MeetingID      uuid.UUID         `gorm:"type:uuid;not null;index" json:"meeting_id"`
+	UserID         uuid.UUID         `gorm:"type:uuid;not null;index" json:"user_id"`
+	ParentID       *uuid.UUID        `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	RecipientID    *uuid.UUID        `gorm:"type:uuid;index" json:"recipient_id,omitempty"`
+	Type           MessageType       `gorm:"type:varchar(20);default:'text'" json:"type"`
+	Content        string            `gorm:"type:text;not null" json:"content"`
+	FileURL        string            `gorm:"type:text" json:"file_url,omitempty"`
+	TemplateKey    SystemMessageKey  `gorm:"type:varchar(64)" json:"template_key,omitempty"`
+	TemplateParams map[string]string `gorm:"type:jsonb;serializer:json" json:"template_params,omitempty"`
+	Mentions       []uuid.UUID       `gorm:"type:jsonb;serializer:json" json:"mentions,omitempty"`
+	IsFlagged      bool              `gorm:"default:false;index" json:"-"`
+	FlagReason     string            `gorm:"type:text" json:"-"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt    `gorm:"index" json:"-"`
 
 	// Relationships
-	Meeting Meeting `gorm:"foreignKey:MeetingID" json:"meeting,omitempty"`
-	User    User    `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Meeting   Meeting           `gorm:"foreignKey:MeetingID" json:"meeting,omitempty"`
+	User      User              `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Reactions []MessageReaction `gorm:"foreignKey:MessageID" json:"reactions,omitempty"`
 }
 
 // BeforeCreate hook to generate UUID
@@ -44,26 +52,90 @@ func (Message) TableName() string {
 	return "messages"
 }
 
+// IsPrivate reports whether the message is a direct message to one participant
+func (m *Message) IsPrivate() bool {
+	return m.RecipientID != nil
+}
+
+// IsVisibleTo reports whether a user may see the message
+func (m *Message) IsVisibleTo(userID uuid.UUID) bool {
+	if !m.IsPrivate() {
+		return true
+	}
+	return m.UserID == userID || *m.RecipientID == userID
+}
+
 // MessageResponse represents the message data sent in API responses
 type MessageResponse struct {
-	ID        uuid.UUID    `json:"id"`
-	MeetingID uuid.UUID    `json:"meeting_id"`
-	User      UserResponse `json:"user"`
-	Type      MessageType  `json:"type"`
-	Content   string       `json:"content"`
-	FileURL   string       `json:"file_url,omitempty"`
-	CreatedAt time.Time    `json:"created_at"`
+	ID             uuid.UUID         `json:"id"`
+	MeetingID      uuid.UUID         `json:"meeting_id"`
+	ParentID       *uuid.UUID        `json:"parent_id,omitempty"`
+	RecipientID    *uuid.UUID        `json:"recipient_id,omitempty"`
+	User           UserResponse      `json:"user"`
+	Type           MessageType       `json:"type"`
+	Content        string            `json:"content"`
+	FileURL        string            `json:"file_url,omitempty"`
+	TemplateKey    SystemMessageKey  `json:"template_key,omitempty"`
+	TemplateParams map[string]string `json:"template_params,omitempty"`
+	Mentions       []uuid.UUID       `json:"mentions,omitempty"`
+	Reactions      []ReactionCount   `json:"reactions"`
+	CreatedAt      time.Time         `json:"created_at"`
 }
 
 // ToResponse converts Message model to MessageResponse
 func (m *Message) ToResponse() MessageResponse {
 	return MessageResponse{
-		ID:        m.ID,
-		MeetingID: m.MeetingID,
-		User:      m.User.ToResponse(),
-		Type:      m.Type,
-		Content:   m.Content,
-		FileURL:   m.FileURL,
-		CreatedAt: m.CreatedAt,
+		ID:             m.ID,
+		MeetingID:      m.MeetingID,
+		ParentID:       m.ParentID,
+		RecipientID:    m.RecipientID,
+		User:           m.User.ToResponse(),
+		Type:           m.Type,
+		Content:        m.Content,
+		FileURL:        m.FileURL,
+		TemplateKey:    m.TemplateKey,
+		TemplateParams: m.TemplateParams,
+		Mentions:       m.Mentions,
+		Reactions:      AggregateReactions(m.Reactions),
+		CreatedAt:      m.CreatedAt,
+	}
+}
+
+// FlaggedMessageResponse represents a flagged message shown to hosts and moderators
+type FlaggedMessageResponse struct {
+	MessageResponse
+	FlagReason string `json:"flag_reason"`
+}
+
+// ToFlaggedResponse converts Message model to FlaggedMessageResponse
+func (m *Message) ToFlaggedResponse() FlaggedMessageResponse {
+	return FlaggedMessageResponse{
+		MessageResponse: m.ToResponse(),
+		FlagReason:      m.FlagReason,
+	}
+}
+
+// MessageSearchResult is a message matched by a full-text search
+type MessageSearchResult struct {
+	Message Message
+	Snippet string
+	Rank    float64
+}
+
+// MessageSearchResponse represents a search hit sent in API responses
+type MessageSearchResponse struct {
+	Message MessageResponse        `json:"message"`
+	Meeting MeetingSummaryResponse `json:"meeting"`
+	Snippet string                 `json:"snippet"`
+	Rank    float64                `json:"rank"`
+}
+
+// ToResponse converts MessageSearchResult to MessageSearchResponse
+func (r *MessageSearchResult) ToResponse() MessageSearchResponse {
+	return MessageSearchResponse{
+		Message: r.Message.ToResponse(),
+		Meeting: r.Message.Meeting.ToSummaryResponse(),
+		Snippet: r.Snippet,
+		Rank:    r.Rank,
 	}
 }
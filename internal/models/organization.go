@@ -0,0 +1,124 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrganizationRole is a member's role in an organization
+type OrganizationRole string
+
+const (
+	OrganizationRoleOwner  OrganizationRole = "owner"
+	OrganizationRoleAdmin  OrganizationRole = "admin"
+	OrganizationRoleMember OrganizationRole = "member"
+)
+
+// IsValid reports whether the role is known
+func (r OrganizationRole) IsValid() bool {
+	switch r {
+	case OrganizationRoleOwner, OrganizationRoleAdmin, OrganizationRoleMember:
+		return true
+	}
+	return false
+}
+
+// CanManage reports whether the role may change the organization and its
+// members
+func (r OrganizationRole) CanManage() bool {
+	return r == OrganizationRoleOwner || r == OrganizationRoleAdmin
+}
+
+// Organization groups users so they can own and attend meetings together
+type Organization struct {
+	ID   uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name string    `gorm:"size:100;not null" json:"name"`
+	// DefaultSettings apply to the organization's meetings created without
+	// settings; nil uses the server defaults
+	DefaultSettings *MeetingSettings `gorm:"type:jsonb;serializer:json" json:"default_settings"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+
+	// Relationships
+	Members []OrganizationMember `gorm:"foreignKey:OrganizationID" json:"members,omitempty"`
+}
+
+// BeforeCreate hook to generate UUID
+func (o *Organization) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for Organization model
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+// OrganizationMember is a user's membership of an organization
+type OrganizationMember struct {
+	ID             uuid.UUID        `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrganizationID uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_organization_members_org_user" json:"organization_id"`
+	UserID         uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_organization_members_org_user;index" json:"user_id"`
+	Role           OrganizationRole `gorm:"type:varchar(20);not null;default:'member'" json:"role"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+
+	// Relationships
+	Organization Organization `gorm:"foreignKey:OrganizationID" json:"-"`
+	User         User         `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// BeforeCreate hook to generate UUID
+func (m *OrganizationMember) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for OrganizationMember model
+func (OrganizationMember) TableName() string {
+	return "organization_members"
+}
+
+// OrganizationResponse represents an organization in API responses, with
+// the current user's role in it
+type OrganizationResponse struct {
+	ID              uuid.UUID        `json:"id"`
+	Name            string           `json:"name"`
+	DefaultSettings *MeetingSettings `json:"default_settings"`
+	Role            OrganizationRole `json:"role"`
+	CreatedAt       time.Time        `json:"created_at"`
+}
+
+// ToResponse converts Organization model to OrganizationResponse for a
+// member with the given role
+func (o *Organization) ToResponse(role OrganizationRole) OrganizationResponse {
+	return OrganizationResponse{
+		ID:              o.ID,
+		Name:            o.Name,
+		DefaultSettings: o.DefaultSettings,
+		Role:            role,
+		CreatedAt:       o.CreatedAt,
+	}
+}
+
+// OrganizationMemberResponse represents a member in API responses
+type OrganizationMemberResponse struct {
+	User     UserResponse     `json:"user"`
+	Role     OrganizationRole `json:"role"`
+	JoinedAt time.Time        `json:"joined_at"`
+}
+
+// ToResponse converts OrganizationMember model to OrganizationMemberResponse
+func (m *OrganizationMember) ToResponse() OrganizationMemberResponse {
+	return OrganizationMemberResponse{
+		User:     m.User.ToResponse(),
+		Role:     m.Role,
+		JoinedAt: m.CreatedAt,
+	}
+}
@@ -30,8 +30,9 @@ type Participant struct {
 	DeletedAt  gorm.DeletedAt  `gorm:"index" json:"-"`
 
 	// Relationships
-	Meeting Meeting `gorm:"foreignKey:MeetingID" json:"meeting,omitempty"`
-	User    User    `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Meeting  Meeting              `gorm:"foreignKey:MeetingID" json:"meeting,omitempty"`
+	User     User                 `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Sessions []ParticipantSession `gorm:"foreignKey:ParticipantID" json:"-"`
 }
 
 // BeforeCreate hook to generate UUID and set joined time
@@ -61,11 +62,19 @@ type ParticipantResponse struct {
 	IsMuted   bool            `json:"is_muted"`
 	IsVideoOn bool            `json:"is_video_on"`
 	IsSharing bool            `json:"is_sharing"`
+	// TotalSeconds is the time spent in the meeting across all sessions;
+	// only reported when the sessions were loaded
+	TotalSeconds int64 `json:"total_seconds,omitempty"`
+}
+
+// CanModerate reports whether the participant may moderate the meeting
+func (p *Participant) CanModerate() bool {
+	return p.Role == ParticipantRoleHost || p.Role == ParticipantRoleModerator
 }
 
 // ToResponse converts Participant model to ParticipantResponse
 func (p *Participant) ToResponse() ParticipantResponse {
-	return ParticipantResponse{
+	resp := ParticipantResponse{
 		ID:        p.ID,
 		MeetingID: p.MeetingID,
 		User:      p.User.ToResponse(),
@@ -76,4 +85,8 @@ func (p *Participant) ToResponse() ParticipantResponse {
 		IsVideoOn: p.IsVideoOn,
 		IsSharing: p.IsSharing,
 	}
+	if len(p.Sessions) > 0 {
+		resp.TotalSeconds = int64(TotalSessionDuration(p.Sessions, time.Now()).Seconds())
+	}
+	return resp
 }
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ParticipantSession is one continuous interval a participant spent in a
+// meeting. A participant who leaves and rejoins has one session per visit.
+type ParticipantSession struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ParticipantID uuid.UUID  `gorm:"type:uuid;not null;index" json:"participant_id"`
+	MeetingID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"meeting_id"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	JoinedAt      time.Time  `gorm:"not null" json:"joined_at"`
+	LeftAt        *time.Time `json:"left_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// BeforeCreate hook to generate UUID and set joined time
+func (s *ParticipantSession) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	if s.JoinedAt.IsZero() {
+		s.JoinedAt = time.Now()
+	}
+	return nil
+}
+
+// TableName specifies the table name for ParticipantSession model
+func (ParticipantSession) TableName() string {
+	return "participant_sessions"
+}
+
+// Duration returns the length of the session, counting an open session up to now
+func (s *ParticipantSession) Duration(now time.Time) time.Duration {
+	end := now
+	if s.LeftAt != nil {
+		end = *s.LeftAt
+	}
+	if end.Before(s.JoinedAt) {
+		return 0
+	}
+	return end.Sub(s.JoinedAt)
+}
+
+// TotalSessionDuration sums the duration of the given sessions
+func TotalSessionDuration(sessions []ParticipantSession, now time.Time) time.Duration {
+	var total time.Duration
+	for i := range sessions {
+		total += sessions[i].Duration(now)
+	}
+	return total
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MessageReaction is a single emoji reaction left by a user on a message
+type MessageReaction struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	MessageID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_message_reactions_unique" json:"message_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_message_reactions_unique;index" json:"user_id"`
+	Emoji     string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_message_reactions_unique" json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BeforeCreate hook to generate UUID
+func (r *MessageReaction) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for MessageReaction model
+func (MessageReaction) TableName() string {
+	return "message_reactions"
+}
+
+// ReactionCount represents the aggregated reactions for one emoji on a message
+type ReactionCount struct {
+	Emoji   string      `json:"emoji"`
+	Count   int         `json:"count"`
+	UserIDs []uuid.UUID `json:"user_ids"`
+}
+
+// AggregateReactions groups reactions by emoji, preserving the order in which
+// each emoji was first used
+func AggregateReactions(reactions []MessageReaction) []ReactionCount {
+	counts := make([]ReactionCount, 0)
+	index := make(map[string]int)
+
+	for _, r := range reactions {
+		i, ok := index[r.Emoji]
+		if !ok {
+			i = len(counts)
+			index[r.Emoji] = i
+			counts = append(counts, ReactionCount{Emoji: r.Emoji})
+		}
+		counts[i].Count++
+		counts[i].UserIDs = append(counts[i].UserIDs, r.UserID)
+	}
+
+	return counts
+}
@@ -0,0 +1,56 @@
+package models
+
+import "strings"
+
+// SystemMessageKey identifies the template of an automatic system message.
+// Clients localise system messages by key and parameters; the stored content
+// is the English rendering used as a fallback and in exports.
+type SystemMessageKey string
+
+const (
+	SystemMessageParticipantJoined   SystemMessageKey = "participant.joined"
+	SystemMessageParticipantLeft     SystemMessageKey = "participant.left"
+	SystemMessageParticipantAdmitted SystemMessageKey = "participant.admitted"
+	SystemMessageParticipantPromoted SystemMessageKey = "participant.promoted"
+	SystemMessageParticipantDemoted  SystemMessageKey = "participant.demoted"
+	SystemMessageScreenShareStarted  SystemMessageKey = "screen_share.started"
+	SystemMessageScreenShareStopped  SystemMessageKey = "screen_share.stopped"
+	SystemMessageMeetingEnded        SystemMessageKey = "meeting.ended"
+	SystemMessageMeetingEndingSoon   SystemMessageKey = "meeting.ending_soon"
+	SystemMessageMeetingEndedIdle    SystemMessageKey = "meeting.ended_idle"
+	SystemMessageMeetingEndedMaxTime SystemMessageKey = "meeting.ended_max_duration"
+	SystemMessageMeetingEndedByAdmin SystemMessageKey = "meeting.ended_by_admin"
+)
+
+// systemMessageTemplates holds the English fallback for each key.
+// Placeholders are written as {param}.
+var systemMessageTemplates = map[SystemMessageKey]string{
+	SystemMessageParticipantJoined:   "{name} joined",
+	SystemMessageParticipantLeft:     "{name} left",
+	SystemMessageParticipantAdmitted: "{name} was admitted by {by}",
+	SystemMessageParticipantPromoted: "{name} was promoted to {role}",
+	SystemMessageParticipantDemoted:  "{name} is no longer a moderator",
+	SystemMessageScreenShareStarted:  "{name} started screen sharing",
+	SystemMessageScreenShareStopped:  "{name} stopped screen sharing",
+	SystemMessageMeetingEnded:        "{name} ended the meeting",
+	SystemMessageMeetingEndingSoon:   "The meeting will end in {minutes} minutes",
+	SystemMessageMeetingEndedIdle:    "The meeting ended because nobody was connected",
+	SystemMessageMeetingEndedMaxTime: "The meeting reached its maximum duration and ended",
+	SystemMessageMeetingEndedByAdmin: "The meeting was ended by an administrator",
+}
+
+// Render returns the English text for the key with params substituted
+func (k SystemMessageKey) Render(params map[string]string) string {
+	text, ok := systemMessageTemplates[k]
+	if !ok {
+		text = string(k)
+	}
+
+	// One pass, so placeholders inside substituted values (e.g. a display
+	// name containing "{by}") are left as they are
+	pairs := make([]string, 0, 2*len(params))
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
@@ -7,21 +7,44 @@ import (
 	"gorm.io/gorm"
 )
 
+// UserRole is a user's role across the whole deployment
+type UserRole string
+
+const (
+	UserRoleMember UserRole = "member"
+	UserRoleAdmin  UserRole = "admin"
+)
+
+// IsValid reports whether the role is known
+func (r UserRole) IsValid() bool {
+	return r == UserRoleMember || r == UserRoleAdmin
+}
+
 type User struct {
-	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	Email     string         `gorm:"uniqueIndex;not null" json:"email"`
-	Username  string         `gorm:"uniqueIndex;not null" json:"username"`
-	Password  string         `gorm:"not null" json:"-"` // Never send password in JSON
-	Name      string         `gorm:"not null" json:"name"`
-	AvatarURL string         `gorm:"type:text" json:"avatar_url"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID               uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Email            string         `gorm:"uniqueIndex;not null" json:"email"`
+	Username         string         `gorm:"uniqueIndex;not null" json:"username"`
+	Password         string         `gorm:"not null" json:"-"` // Never send password in JSON
+	Name             string         `gorm:"not null" json:"name"`
+	AvatarURL        string         `gorm:"type:text" json:"avatar_url"`
+	IsGuest          bool           `gorm:"not null;default:false" json:"is_guest"` // Anonymous meeting guest without credentials
+	Role             UserRole       `gorm:"type:varchar(20);not null;default:'member'" json:"role"`
+	IsServiceAccount bool           `gorm:"not null;default:false" json:"is_service_account"` // Credential-less automation user managed by OwnerID through API tokens
+	OwnerID          *uuid.UUID     `gorm:"type:uuid;index" json:"owner_id,omitempty"`
+	EmailVerifiedAt  *time.Time     `json:"email_verified_at"`
+	TOTPSecret       string         `gorm:"size:64;not null;default:''" json:"-"` // Set during setup, before TOTPEnabledAt
+	TOTPEnabledAt    *time.Time     `json:"-"`
+	TOTPLastStep     int64          `gorm:"not null;default:0" json:"-"` // Last accepted time step; codes cannot be replayed
+	SuspendedAt      *time.Time     `json:"-"`                           // Set by an admin; suspended users cannot log in or use API tokens
+	SuspensionReason string         `gorm:"size:500;not null;default:''" json:"-"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
-	HostedMeetings     []Meeting      `gorm:"foreignKey:HostID" json:"hosted_meetings,omitempty"`
-	MeetingParticipant []Participant  `gorm:"foreignKey:UserID" json:"participations,omitempty"`
-	Messages           []Message      `gorm:"foreignKey:UserID" json:"messages,omitempty"`
+	HostedMeetings     []Meeting     `gorm:"foreignKey:HostID" json:"hosted_meetings,omitempty"`
+	MeetingParticipant []Participant `gorm:"foreignKey:UserID" json:"participations,omitempty"`
+	Messages           []Message     `gorm:"foreignKey:UserID" json:"messages,omitempty"`
 }
 
 // BeforeCreate hook to generate UUID
@@ -32,6 +55,21 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// IsEmailVerified reports whether the user confirmed their email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsMFAEnabled reports whether logins need a second factor
+func (u *User) IsMFAEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// IsSuspended reports whether an admin suspended the account
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
 // TableName specifies the table name for User model
 func (User) TableName() string {
 	return "users"
@@ -39,22 +77,53 @@ func (User) TableName() string {
 
 // UserResponse represents the user data sent in API responses
 type UserResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	Username  string    `json:"username"`
-	Name      string    `json:"name"`
-	AvatarURL string    `json:"avatar_url"`
-	CreatedAt time.Time `json:"created_at"`
+	ID               uuid.UUID  `json:"id"`
+	Email            string     `json:"email"`
+	Username         string     `json:"username"`
+	Name             string     `json:"name"`
+	AvatarURL        string     `json:"avatar_url"`
+	IsGuest          bool       `json:"is_guest"`
+	Role             UserRole   `json:"role"`
+	IsServiceAccount bool       `json:"is_service_account"`
+	OwnerID          *uuid.UUID `json:"owner_id,omitempty"`
+	EmailVerified    bool       `json:"email_verified"`
+	MFAEnabled       bool       `json:"mfa_enabled"`
+	CreatedAt        time.Time  `json:"created_at"`
 }
 
 // ToResponse converts User model to UserResponse
 func (u *User) ToResponse() UserResponse {
 	return UserResponse{
-		ID:        u.ID,
-		Email:     u.Email,
-		Username:  u.Username,
-		Name:      u.Name,
-		AvatarURL: u.AvatarURL,
-		CreatedAt: u.CreatedAt,
+		ID:               u.ID,
+		Email:            u.Email,
+		Username:         u.Username,
+		Name:             u.Name,
+		AvatarURL:        u.AvatarURL,
+		IsGuest:          u.IsGuest,
+		Role:             u.Role,
+		IsServiceAccount: u.IsServiceAccount,
+		OwnerID:          u.OwnerID,
+		EmailVerified:    u.IsEmailVerified(),
+		MFAEnabled:       u.IsMFAEnabled(),
+		CreatedAt:        u.CreatedAt,
+	}
+}
+
+// AdminUserResponse is a user as shown to admins, with account state other
+// users do not see
+type AdminUserResponse struct {
+	UserResponse
+	SuspendedAt      *time.Time `json:"suspended_at"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// ToAdminResponse converts User model to AdminUserResponse
+func (u *User) ToAdminResponse() AdminUserResponse {
+	return AdminUserResponse{
+		UserResponse:     u.ToResponse(),
+		SuspendedAt:      u.SuspendedAt,
+		SuspensionReason: u.SuspensionReason,
+		UpdatedAt:        u.UpdatedAt,
 	}
 }
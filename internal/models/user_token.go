@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserTokenPurpose is what a single-use user token can be redeemed for
+type UserTokenPurpose string
+
+const (
+	UserTokenVerifyEmail   UserTokenPurpose = "verify_email"
+	UserTokenResetPassword UserTokenPurpose = "reset_password"
+)
+
+// UserToken is a single-use token emailed to a user. Only the SHA-256 hash of
+// the token is stored.
+type UserToken struct {
+	ID        uuid.UUID        `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID        `gorm:"type:uuid;not null;index" json:"user_id"`
+	Purpose   UserTokenPurpose `gorm:"type:varchar(20);not null" json:"purpose"`
+	TokenHash string           `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time        `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time       `json:"used_at"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// BeforeCreate hook to generate UUID
+func (t *UserToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName specifies the table name for UserToken model
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
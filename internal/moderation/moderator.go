@@ -0,0 +1,95 @@
+package moderation
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Action is the decision a moderator takes on a message
+type Action string
+
+const (
+	ActionAllow  Action = "allow"
+	ActionReject Action = "reject"
+	ActionMask   Action = "mask"
+	ActionFlag   Action = "flag"
+)
+
+// ParseAction converts a configuration value to an Action, falling back to the default
+func ParseAction(value string, fallback Action) Action {
+	switch Action(strings.ToLower(strings.TrimSpace(value))) {
+	case ActionAllow:
+		return ActionAllow
+	case ActionReject:
+		return ActionReject
+	case ActionMask:
+		return ActionMask
+	case ActionFlag:
+		return ActionFlag
+	default:
+		return fallback
+	}
+}
+
+// Input is the message being checked
+type Input struct {
+	MeetingID uuid.UUID
+	UserID    uuid.UUID
+	Content   string
+}
+
+// Result is the outcome of moderating a message
+type Result struct {
+	Action Action
+	// Content is the (possibly masked) content to store
+	Content string
+	Reason  string
+}
+
+// Moderator inspects chat messages before they are stored and broadcast
+type Moderator interface {
+	Moderate(ctx context.Context, input Input) (Result, error)
+}
+
+// Allow returns a result that lets the content through unchanged
+func Allow(content string) Result {
+	return Result{Action: ActionAllow, Content: content}
+}
+
+// Chain runs moderators in order. A rejection stops the chain, masked content
+// is passed on to the next moderator, and flag reasons are accumulated.
+type Chain []Moderator
+
+// Moderate implements Moderator
+func (c Chain) Moderate(ctx context.Context, input Input) (Result, error) {
+	result := Allow(input.Content)
+	var reasons []string
+
+	for _, m := range c {
+		r, err := m.Moderate(ctx, input)
+		if err != nil {
+			return Result{}, err
+		}
+
+		switch r.Action {
+		case ActionReject:
+			return r, nil
+		case ActionMask:
+			input.Content = r.Content
+			result.Content = r.Content
+			if result.Action == ActionAllow {
+				result.Action = ActionMask
+			}
+		case ActionFlag:
+			result.Action = ActionFlag
+			if r.Reason != "" {
+				reasons = append(reasons, r.Reason)
+			}
+		}
+	}
+
+	result.Reason = strings.Join(reasons, "; ")
+	return result, nil
+}
@@ -0,0 +1,97 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookModerator sends messages to an external HTTP classifier
+type WebhookModerator struct {
+	url    string
+	client *http.Client
+}
+
+type webhookRequest struct {
+	MeetingID uuid.UUID `json:"meeting_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Content   string    `json:"content"`
+}
+
+type webhookResponse struct {
+	Action  Action `json:"action"`
+	Content string `json:"content"`
+	Reason  string `json:"reason"`
+}
+
+// NewWebhookModerator creates a moderator that POSTs each message to url and
+// expects a JSON body of the form {"action": "allow|reject|mask|flag",
+// "content": "...", "reason": "..."}
+func NewWebhookModerator(url string, timeout time.Duration) *WebhookModerator {
+	return &WebhookModerator{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Moderate implements Moderator. The classifier is advisory: if it cannot be
+// reached the message is allowed so chat keeps working.
+func (m *WebhookModerator) Moderate(ctx context.Context, input Input) (Result, error) {
+	result, err := m.classify(ctx, input)
+	if err != nil {
+		log.Printf("[Moderation] Webhook classifier failed, allowing message: %v", err)
+		return Allow(input.Content), nil
+	}
+	return result, nil
+}
+
+func (m *WebhookModerator) classify(ctx context.Context, input Input) (Result, error) {
+	body, err := json.Marshal(webhookRequest{
+		MeetingID: input.MeetingID,
+		UserID:    input.UserID,
+		Content:   input.Content,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(body))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var decoded webhookResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return Result{}, err
+	}
+
+	action := ParseAction(string(decoded.Action), ActionAllow)
+	switch action {
+	case ActionMask:
+		if decoded.Content == "" {
+			return Result{}, fmt.Errorf("mask action without content")
+		}
+		return Result{Action: ActionMask, Content: decoded.Content, Reason: decoded.Reason}, nil
+	case ActionReject, ActionFlag:
+		return Result{Action: action, Content: input.Content, Reason: decoded.Reason}, nil
+	default:
+		return Allow(input.Content), nil
+	}
+}
@@ -0,0 +1,54 @@
+package moderation
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// WordListModerator matches content against a fixed list of blocked words
+type WordListModerator struct {
+	pattern *regexp.Regexp
+	action  Action
+}
+
+// NewWordListModerator creates a moderator that applies action when any of the
+// words appears in a message. Matching is case-insensitive and on whole words.
+// It returns nil when the list is empty.
+func NewWordListModerator(words []string, action Action) *WordListModerator {
+	quoted := make([]string, 0, len(words))
+	for _, w := range words {
+		w = strings.TrimSpace(w)
+		if w != "" {
+			quoted = append(quoted, regexp.QuoteMeta(w))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+
+	return &WordListModerator{
+		pattern: regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`),
+		action:  action,
+	}
+}
+
+// Moderate implements Moderator
+func (m *WordListModerator) Moderate(ctx context.Context, input Input) (Result, error) {
+	if !m.pattern.MatchString(input.Content) {
+		return Allow(input.Content), nil
+	}
+
+	switch m.action {
+	case ActionReject:
+		return Result{Action: ActionReject, Reason: "message contains blocked words"}, nil
+	case ActionFlag:
+		return Result{Action: ActionFlag, Content: input.Content, Reason: "message contains blocked words"}, nil
+	default:
+		masked := m.pattern.ReplaceAllStringFunc(input.Content, func(word string) string {
+			return strings.Repeat("*", utf8.RuneCountInString(word))
+		})
+		return Result{Action: ActionMask, Content: masked}, nil
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var ErrAPITokenNotFound = errors.New("api token not found")
+
+type APITokenRepository interface {
+	Create(token *models.APIToken) error
+	FindByHash(tokenHash string) (*models.APIToken, error)
+	FindByUserID(userID uuid.UUID) ([]models.APIToken, error)
+	Revoke(id, userID uuid.UUID) error
+	RevokeAllForUser(userID uuid.UUID) error
+	TouchLastUsed(id uuid.UUID, at time.Time) error
+}
+
+type apiTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAPITokenRepository(db *gorm.DB) APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+func (r *apiTokenRepository) Create(token *models.APIToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByHash returns the token with its user; deleted users' tokens are not
+// found
+func (r *apiTokenRepository) FindByHash(tokenHash string) (*models.APIToken, error) {
+	var token models.APIToken
+	err := r.db.Joins("User").Where("api_tokens.token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPITokenNotFound
+		}
+		return nil, err
+	}
+	if token.User.ID == uuid.Nil {
+		return nil, ErrAPITokenNotFound
+	}
+	return &token, nil
+}
+
+// FindByUserID returns the user's tokens that have not been revoked, newest
+// first
+func (r *apiTokenRepository) FindByUserID(userID uuid.UUID) ([]models.APIToken, error) {
+	var tokens []models.APIToken
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *apiTokenRepository) Revoke(id, userID uuid.UUID) error {
+	result := r.db.Model(&models.APIToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPITokenNotFound
+	}
+	return nil
+}
+
+func (r *apiTokenRepository) RevokeAllForUser(userID uuid.UUID) error {
+	return r.db.Model(&models.APIToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *apiTokenRepository) TouchLastUsed(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.APIToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditEventListParams holds the filters for reading the audit log
+type AuditEventListParams struct {
+	MeetingID    *uuid.UUID
+	ActorID      *uuid.UUID
+	TargetUserID *uuid.UUID
+	Action       models.AuditAction
+	From         *time.Time
+	To           *time.Time
+	Offset       int
+	Limit        int
+}
+
+// AuditEventRepository has no update; events are only ever added, and
+// removed once they fall out of the retention window
+type AuditEventRepository interface {
+	Create(event *models.AuditEvent) error
+	List(params AuditEventListParams) ([]models.AuditEvent, error)
+	DeleteBefore(cutoff time.Time) (int64, error)
+}
+
+type auditEventRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditEventRepository(db *gorm.DB) AuditEventRepository {
+	return &auditEventRepository{db: db}
+}
+
+func (r *auditEventRepository) Create(event *models.AuditEvent) error {
+	return r.db.Create(event).Error
+}
+
+// List returns one page of audit events matching the filters, newest first
+func (r *auditEventRepository) List(params AuditEventListParams) ([]models.AuditEvent, error) {
+	query := r.db.Model(&models.AuditEvent{})
+
+	if params.MeetingID != nil {
+		query = query.Where("meeting_id = ?", *params.MeetingID)
+	}
+	if params.ActorID != nil {
+		query = query.Where("actor_id = ?", *params.ActorID)
+	}
+	if params.TargetUserID != nil {
+		query = query.Where("target_user_id = ?", *params.TargetUserID)
+	}
+	if params.Action != "" {
+		query = query.Where("action = ?", params.Action)
+	}
+	if params.From != nil {
+		query = query.Where("created_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where("created_at <= ?", *params.To)
+	}
+
+	var events []models.AuditEvent
+	err := query.Order("created_at DESC, id DESC").
+		Offset(params.Offset).
+		Limit(params.Limit).
+		Find(&events).Error
+	return events, err
+}
+
+// DeleteBefore removes the events created before the cutoff and returns how
+// many were removed
+func (r *auditEventRepository) DeleteBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", cutoff).Delete(&models.AuditEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInviteNotFound    = errors.New("invite not found")
+	ErrInviteUnavailable = errors.New("invite is expired, revoked or used up")
+)
+
+type InviteRepository interface {
+	Create(invite *models.MeetingInvite) error
+	FindByID(id uuid.UUID) (*models.MeetingInvite, error)
+	FindByMeetingID(meetingID uuid.UUID) ([]models.MeetingInvite, error)
+	Consume(id uuid.UUID) error
+	Revoke(id, meetingID uuid.UUID) error
+}
+
+type inviteRepository struct {
+	db *gorm.DB
+}
+
+func NewInviteRepository(db *gorm.DB) InviteRepository {
+	return &inviteRepository{db: db}
+}
+
+func (r *inviteRepository) Create(invite *models.MeetingInvite) error {
+	return r.db.Create(invite).Error
+}
+
+func (r *inviteRepository) FindByID(id uuid.UUID) (*models.MeetingInvite, error) {
+	var invite models.MeetingInvite
+	err := r.db.Where("id = ?", id).First(&invite).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteNotFound
+		}
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (r *inviteRepository) FindByMeetingID(meetingID uuid.UUID) ([]models.MeetingInvite, error) {
+	var invites []models.MeetingInvite
+	err := r.db.Where("meeting_id = ?", meetingID).
+		Order("created_at DESC").
+		Find(&invites).Error
+	return invites, err
+}
+
+// Consume uses up one admission of the invite, failing if none are left
+func (r *inviteRepository) Consume(id uuid.UUID) error {
+	result := r.db.Model(&models.MeetingInvite{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ?", id, time.Now()).
+		Where("max_uses = 0 OR uses < max_uses").
+		Update("uses", gorm.Expr("uses + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInviteUnavailable
+	}
+	return nil
+}
+
+func (r *inviteRepository) Revoke(id, meetingID uuid.UUID) error {
+	result := r.db.Model(&models.MeetingInvite{}).
+		Where("id = ? AND meeting_id = ? AND revoked_at IS NULL", id, meetingID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInviteNotFound
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// LoginAttemptListParams holds the filters for reading the login audit log
+type LoginAttemptListParams struct {
+	UserID    *uuid.UUID
+	Email     string
+	IPAddress string
+	Success   *bool
+	From      *time.Time
+	To        *time.Time
+	Offset    int
+	Limit     int
+}
+
+type LoginAttemptRepository interface {
+	Create(attempt *models.LoginAttempt) error
+	List(params LoginAttemptListParams) ([]models.LoginAttempt, error)
+}
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Create(attempt *models.LoginAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+// List returns one page of login attempts matching the filters, newest first
+func (r *loginAttemptRepository) List(params LoginAttemptListParams) ([]models.LoginAttempt, error) {
+	query := r.db.Model(&models.LoginAttempt{})
+
+	if params.UserID != nil {
+		query = query.Where("user_id = ?", *params.UserID)
+	}
+	if params.Email != "" {
+		query = query.Where("email = ?", params.Email)
+	}
+	if params.IPAddress != "" {
+		query = query.Where("ip_address = ?", params.IPAddress)
+	}
+	if params.Success != nil {
+		query = query.Where("success = ?", *params.Success)
+	}
+	if params.From != nil {
+		query = query.Where("created_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where("created_at <= ?", *params.To)
+	}
+
+	var attempts []models.LoginAttempt
+	err := query.Order("created_at DESC, id DESC").
+		Offset(params.Offset).
+		Limit(params.Limit).
+		Find(&attempts).Error
+	return attempts, err
+}
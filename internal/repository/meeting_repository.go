@@ -1,30 +1,106 @@
 package repository
 
 import (
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/meet-app/backend/internal/meetingcode"
 	"github.com/meet-app/backend/internal/models"
 	"gorm.io/gorm"
 )
 
 var (
-	ErrMeetingNotFound      = errors.New("meeting not found")
-	ErrMeetingCodeExists    = errors.New("meeting code already exists")
+	ErrMeetingNotFound     = errors.New("meeting not found")
+	ErrMeetingCodeExists   = errors.New("meeting code already exists")
+	ErrInvalidCursor       = errors.New("invalid pagination cursor")
+	ErrMeetingAlreadyEnded = errors.New("meeting has already ended")
 )
 
+// MeetingListRole restricts a meeting listing to how the user took part
+type MeetingListRole string
+
+const (
+	MeetingListRoleAny      MeetingListRole = ""
+	MeetingListRoleHosted   MeetingListRole = "hosted"
+	MeetingListRoleAttended MeetingListRole = "attended"
+)
+
+// MeetingListParams holds the filters for listing a user's meetings. With
+// OrganizationID set, every meeting of the organization is listed unless Role
+// narrows it to the user's own.
+type MeetingListParams struct {
+	UserID         uuid.UUID
+	OrganizationID *uuid.UUID
+	Role           MeetingListRole
+	Status         models.MeetingStatus
+	From           *time.Time
+	To             *time.Time
+	Query          string
+	Cursor         *MeetingCursor
+	Limit          int
+}
+
+// MeetingCursor is the keyset position after the last meeting of a page
+type MeetingCursor struct {
+	Time time.Time
+	ID   uuid.UUID
+}
+
+// Encode returns the opaque string form of the cursor
+func (c MeetingCursor) Encode() string {
+	raw := c.Time.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeMeetingCursor parses a cursor produced by MeetingCursor.Encode
+func DecodeMeetingCursor(value string) (*MeetingCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &MeetingCursor{Time: t, ID: id}, nil
+}
+
+// normalizedCodeExpr matches meetingcode.Normalize so lookups ignore case
+// and dashes; it is backed by an expression index
+const normalizedCodeExpr = "REPLACE(LOWER(code), '-', '')"
+
+// meetingTimeExpr is when a meeting happened (or will happen), used for
+// ordering and date filtering of meeting listings
+const meetingTimeExpr = "COALESCE(m.started_at, m.scheduled_at, m.created_at)"
+
 type MeetingRepository interface {
 	Create(meeting *models.Meeting) error
 	FindByID(id uuid.UUID) (*models.Meeting, error)
 	FindByCode(code string) (*models.Meeting, error)
 	FindByHostID(hostID uuid.UUID) ([]models.Meeting, error)
 	FindActiveMeetings() ([]models.Meeting, error)
+	FindUserMeetings(params MeetingListParams) ([]models.MeetingListItem, *MeetingCursor, error)
 	Update(meeting *models.Meeting) error
 	Delete(id uuid.UUID) error
 	UpdateStatus(id uuid.UUID, status models.MeetingStatus) error
 	StartMeeting(id uuid.UUID) error
 	EndMeeting(id uuid.UUID) error
+	UpdateCode(id uuid.UUID, code string) error
+	UpdatePasscode(id uuid.UUID, passcodeHash string) error
+	ExpireScheduledMeetings(before time.Time) (int64, error)
 	ExistsByCode(code string) (bool, error)
 }
 
@@ -36,17 +112,14 @@ func NewMeetingRepository(db *gorm.DB) MeetingRepository {
 	return &meetingRepository{db: db}
 }
 
+// Create stores the meeting, failing with ErrMeetingCodeExists if another
+// meeting uses the code
 func (r *meetingRepository) Create(meeting *models.Meeting) error {
-	// Check if code exists
-	exists, err := r.ExistsByCode(meeting.Code)
-	if err != nil {
-		return err
-	}
-	if exists {
+	err := r.db.Create(meeting).Error
+	if isUniqueViolation(err) {
 		return ErrMeetingCodeExists
 	}
-
-	return r.db.Create(meeting).Error
+	return err
 }
 
 func (r *meetingRepository) FindByID(id uuid.UUID) (*models.Meeting, error) {
@@ -63,7 +136,9 @@ func (r *meetingRepository) FindByID(id uuid.UUID) (*models.Meeting, error) {
 
 func (r *meetingRepository) FindByCode(code string) (*models.Meeting, error) {
 	var meeting models.Meeting
-	err := r.db.Preload("Host").Where("code = ?", code).First(&meeting).Error
+	err := r.db.Preload("Host").
+		Where(normalizedCodeExpr+" = ?", meetingcode.Normalize(code)).
+		First(&meeting).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrMeetingNotFound
@@ -88,6 +163,138 @@ func (r *meetingRepository) FindActiveMeetings() ([]models.Meeting, error) {
 	return meetings, err
 }
 
+// FindUserMeetings lists meetings the user hosted or attended, newest first
+// (scheduled meetings soonest first), one page at a time
+func (r *meetingRepository) FindUserMeetings(params MeetingListParams) ([]models.MeetingListItem, *MeetingCursor, error) {
+	var rows []struct {
+		ID                     uuid.UUID
+		SortTime               time.Time
+		ViewerRole             models.ParticipantRole
+		ParticipantCount       int64
+		ActiveParticipantCount int64
+		LastActivityAt         *time.Time
+	}
+
+	attended := "EXISTS (SELECT 1 FROM participants p WHERE p.meeting_id = m.id AND p.user_id = ? AND p.deleted_at IS NULL)"
+
+	query := r.db.Table("meetings AS m").
+		Select(
+			"m.id, "+meetingTimeExpr+" AS sort_time, "+
+				"COALESCE(CASE WHEN m.host_id = ? THEN 'host' ELSE (SELECT p.role FROM participants p "+
+				"WHERE p.meeting_id = m.id AND p.user_id = ? AND p.deleted_at IS NULL LIMIT 1) END, '') AS viewer_role, "+
+				"(SELECT COUNT(*) FROM participants p WHERE p.meeting_id = m.id AND p.deleted_at IS NULL) AS participant_count, "+
+				"(SELECT COUNT(*) FROM participants p WHERE p.meeting_id = m.id AND p.deleted_at IS NULL AND p.left_at IS NULL) AS active_participant_count, "+
+				"GREATEST(m.updated_at, "+
+				"(SELECT MAX(msg.created_at) FROM messages msg WHERE msg.meeting_id = m.id AND msg.deleted_at IS NULL), "+
+				"(SELECT MAX(COALESCE(s.left_at, s.joined_at)) FROM participant_sessions s WHERE s.meeting_id = m.id)) AS last_activity_at",
+			params.UserID, params.UserID,
+		).
+		Where("m.deleted_at IS NULL")
+
+	if params.OrganizationID != nil {
+		query = query.Where("m.organization_id = ?", *params.OrganizationID)
+	}
+
+	switch params.Role {
+	case MeetingListRoleHosted:
+		query = query.Where("m.host_id = ?", params.UserID)
+	case MeetingListRoleAttended:
+		query = query.Where("m.host_id <> ? AND "+attended, params.UserID, params.UserID)
+	default:
+		if params.OrganizationID == nil {
+			query = query.Where("(m.host_id = ? OR "+attended+")", params.UserID, params.UserID)
+		}
+	}
+
+	if params.Status != "" {
+		query = query.Where("m.status = ?", params.Status)
+	}
+	if params.From != nil {
+		query = query.Where(meetingTimeExpr+" >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where(meetingTimeExpr+" <= ?", *params.To)
+	}
+	if params.Query != "" {
+		query = query.Where("m.title ILIKE ?", "%"+escapeLike(params.Query)+"%")
+	}
+
+	// Upcoming meetings read best soonest first, everything else newest first
+	direction, comparison := "DESC", "<"
+	if params.Status == models.MeetingStatusScheduled {
+		direction, comparison = "ASC", ">"
+	}
+	if params.Cursor != nil {
+		query = query.Where(
+			fmt.Sprintf("(%s, m.id) %s (?, ?)", meetingTimeExpr, comparison),
+			params.Cursor.Time, params.Cursor.ID,
+		)
+	}
+
+	// Fetch one extra row to know whether there is another page
+	err := query.Order(fmt.Sprintf("sort_time %s, m.id %s", direction, direction)).
+		Limit(params.Limit + 1).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var next *MeetingCursor
+	if len(rows) > params.Limit {
+		rows = rows[:params.Limit]
+		last := rows[len(rows)-1]
+		next = &MeetingCursor{Time: last.SortTime, ID: last.ID}
+	}
+	if len(rows) == 0 {
+		return []models.MeetingListItem{}, nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	var meetings []models.Meeting
+	if err := r.db.Preload("Host").Where("id IN ?", ids).Find(&meetings).Error; err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[uuid.UUID]models.Meeting, len(meetings))
+	for _, m := range meetings {
+		byID[m.ID] = m
+	}
+
+	// Keep the order from the listing query
+	items := make([]models.MeetingListItem, 0, len(rows))
+	for _, row := range rows {
+		meeting, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		items = append(items, models.MeetingListItem{
+			Meeting:                meeting,
+			ViewerRole:             row.ViewerRole,
+			ParticipantCount:       row.ParticipantCount,
+			ActiveParticipantCount: row.ActiveParticipantCount,
+			LastActivityAt:         row.LastActivityAt,
+		})
+	}
+
+	return items, next, nil
+}
+
+// isUniqueViolation reports whether err is Postgres rejecting a duplicate
+// key, which is how concurrent inserts of the same value are detected
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// escapeLike escapes the LIKE wildcards in a user-supplied search term
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
 func (r *meetingRepository) Update(meeting *models.Meeting) error {
 	return r.db.Save(meeting).Error
 }
@@ -112,18 +319,74 @@ func (r *meetingRepository) StartMeeting(id uuid.UUID) error {
 		}).Error
 }
 
+// UpdateCode replaces the meeting's code, failing if another meeting uses it
+func (r *meetingRepository) UpdateCode(id uuid.UUID, code string) error {
+	err := r.db.Model(&models.Meeting{}).
+		Where("id = ?", id).
+		Update("code", code).Error
+	if isUniqueViolation(err) {
+		return ErrMeetingCodeExists
+	}
+	return err
+}
+
+// EndMeeting marks the meeting ended and every participant still in it as
+// left, closing their open sessions, in a single transaction
 func (r *meetingRepository) EndMeeting(id uuid.UUID) error {
 	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Meeting{}).
+			Where("id = ? AND status <> ?", id, models.MeetingStatusEnded).
+			Updates(map[string]interface{}{
+				"status":   models.MeetingStatusEnded,
+				"ended_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMeetingAlreadyEnded
+		}
+
+		if err := tx.Model(&models.Participant{}).
+			Where("meeting_id = ? AND left_at IS NULL", id).
+			Updates(map[string]interface{}{
+				"left_at":    now,
+				"is_sharing": false,
+			}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.ParticipantSession{}).
+			Where("meeting_id = ? AND left_at IS NULL", id).
+			Update("left_at", now).Error
+	})
+}
+
+// UpdatePasscode stores the meeting's passcode hash; an empty hash removes
+// the passcode
+func (r *meetingRepository) UpdatePasscode(id uuid.UUID, passcodeHash string) error {
 	return r.db.Model(&models.Meeting{}).
 		Where("id = ?", id).
+		Update("passcode_hash", passcodeHash).Error
+}
+
+// ExpireScheduledMeetings ends scheduled meetings that were due (or, without a
+// schedule, created) before the cutoff and never started
+func (r *meetingRepository) ExpireScheduledMeetings(before time.Time) (int64, error) {
+	result := r.db.Model(&models.Meeting{}).
+		Where("status = ? AND COALESCE(scheduled_at, created_at) < ?", models.MeetingStatusScheduled, before).
 		Updates(map[string]interface{}{
 			"status":   models.MeetingStatusEnded,
-			"ended_at": now,
-		}).Error
+			"ended_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
 }
 
 func (r *meetingRepository) ExistsByCode(code string) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.Meeting{}).Where("code = ?", code).Count(&count).Error
+	err := r.db.Model(&models.Meeting{}).
+		Where(normalizedCodeExpr+" = ?", meetingcode.Normalize(code)).
+		Count(&count).Error
 	return count > 0, err
 }
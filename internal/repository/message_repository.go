@@ -2,6 +2,9 @@ package repository
 
 import (
 	"errors"
+	"html"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/meet-app/backend/internal/models"
@@ -12,11 +15,42 @@ var (
 	ErrMessageNotFound = errors.New("message not found")
 )
 
+// searchConfig is the Postgres text search configuration used for chat content.
+// It must match the expression of the GIN index created by the migrations.
+const searchConfig = "english"
+
+// searchVector is written into the SQL rather than bound as a parameter so
+// the planner can match it to the GIN index expression
+const searchVector = "to_tsvector('" + searchConfig + "', m.content)"
+
+// Control characters used by ts_headline to delimit matches; they are swapped
+// for <mark> tags after the snippet has been HTML-escaped.
+const (
+	snippetStartSel = "\x02"
+	snippetStopSel  = "\x03"
+)
+
+// MessageSearchParams holds the query and filters for a full-text message search
+type MessageSearchParams struct {
+	Query     string
+	ViewerID  uuid.UUID
+	MeetingID *uuid.UUID
+	AuthorID  *uuid.UUID
+	From      *time.Time
+	To        *time.Time
+	Offset    int
+	Limit     int
+}
+
 type MessageRepository interface {
 	Create(message *models.Message) error
 	FindByID(id uuid.UUID) (*models.Message, error)
-	FindByMeetingID(meetingID uuid.UUID, limit int) ([]models.Message, error)
-	FindByMeetingIDPaginated(meetingID uuid.UUID, offset, limit int) ([]models.Message, error)
+	FindByMeetingID(meetingID, viewerID uuid.UUID, limit int) ([]models.Message, error)
+	FindByMeetingIDPaginated(meetingID, viewerID uuid.UUID, offset, limit int) ([]models.Message, error)
+	FindReplies(parentID, viewerID uuid.UUID) ([]models.Message, error)
+	Search(params MessageSearchParams) ([]models.MessageSearchResult, error)
+	FindFlaggedByMeetingID(meetingID uuid.UUID) ([]models.Message, error)
+	StreamByMeetingID(meetingID, viewerID uuid.UUID, batchSize int, fn func([]models.Message) error) error
 	Update(message *models.Message) error
 	Delete(id uuid.UUID) error
 	CountByMeetingID(meetingID uuid.UUID) (int64, error)
@@ -36,7 +70,7 @@ func (r *messageRepository) Create(message *models.Message) error {
 
 func (r *messageRepository) FindByID(id uuid.UUID) (*models.Message, error) {
 	var message models.Message
-	err := r.db.Preload("User").Preload("Meeting").
+	err := r.db.Preload("User").Preload("Meeting").Preload("Reactions", orderReactions).
 		Where("id = ?", id).First(&message).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -47,10 +81,11 @@ func (r *messageRepository) FindByID(id uuid.UUID) (*models.Message, error) {
 	return &message, nil
 }
 
-func (r *messageRepository) FindByMeetingID(meetingID uuid.UUID, limit int) ([]models.Message, error) {
+func (r *messageRepository) FindByMeetingID(meetingID, viewerID uuid.UUID, limit int) ([]models.Message, error) {
 	var messages []models.Message
-	query := r.db.Preload("User").
+	query := r.db.Preload("User").Preload("Reactions", orderReactions).
 		Where("meeting_id = ?", meetingID).
+		Scopes(visibleTo(viewerID)).
 		Order("created_at DESC")
 
 	if limit > 0 {
@@ -61,10 +96,11 @@ func (r *messageRepository) FindByMeetingID(meetingID uuid.UUID, limit int) ([]m
 	return messages, err
 }
 
-func (r *messageRepository) FindByMeetingIDPaginated(meetingID uuid.UUID, offset, limit int) ([]models.Message, error) {
+func (r *messageRepository) FindByMeetingIDPaginated(meetingID, viewerID uuid.UUID, offset, limit int) ([]models.Message, error) {
 	var messages []models.Message
-	err := r.db.Preload("User").
+	err := r.db.Preload("User").Preload("Reactions", orderReactions).
 		Where("meeting_id = ?", meetingID).
+		Scopes(visibleTo(viewerID)).
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(limit).
@@ -72,6 +108,152 @@ func (r *messageRepository) FindByMeetingIDPaginated(meetingID uuid.UUID, offset
 	return messages, err
 }
 
+func (r *messageRepository) FindReplies(parentID, viewerID uuid.UUID) ([]models.Message, error) {
+	var messages []models.Message
+	err := r.db.Preload("User").Preload("Reactions", orderReactions).
+		Where("parent_id = ?", parentID).
+		Scopes(visibleTo(viewerID)).
+		Order("created_at ASC").
+		Find(&messages).Error
+	return messages, err
+}
+
+func (r *messageRepository) Search(params MessageSearchParams) ([]models.MessageSearchResult, error) {
+	var rows []struct {
+		ID      uuid.UUID
+		Snippet string
+		Rank    float64
+	}
+
+	query := r.db.Table("messages AS m").
+		Select(
+			"m.id, ts_headline(?, m.content, query, ?) AS snippet, "+
+				"ts_rank("+searchVector+", query) AS rank",
+			searchConfig,
+			"StartSel="+snippetStartSel+", StopSel="+snippetStopSel+", MaxFragments=2",
+		).
+		Joins("JOIN meetings ON meetings.id = m.meeting_id AND meetings.deleted_at IS NULL").
+		Joins("CROSS JOIN websearch_to_tsquery(?, ?) AS query", searchConfig, params.Query).
+		Where(searchVector+" @@ query").
+		Where("m.deleted_at IS NULL").
+		// Only meetings the viewer participated in
+		Where("m.meeting_id IN (?)", r.db.Model(&models.Participant{}).
+			Select("meeting_id").
+			Where("user_id = ?", params.ViewerID)).
+		// Private messages are only visible to their sender and recipient
+		Where("(m.recipient_id IS NULL OR m.user_id = ? OR m.recipient_id = ?)", params.ViewerID, params.ViewerID)
+
+	if params.MeetingID != nil {
+		query = query.Where("m.meeting_id = ?", *params.MeetingID)
+	}
+	if params.AuthorID != nil {
+		query = query.Where("m.user_id = ?", *params.AuthorID)
+	}
+	if params.From != nil {
+		query = query.Where("m.created_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where("m.created_at <= ?", *params.To)
+	}
+
+	err := query.Order("rank DESC, m.created_at DESC").
+		Offset(params.Offset).
+		Limit(params.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []models.MessageSearchResult{}, nil
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	var messages []models.Message
+	err = r.db.Preload("User").Preload("Meeting").Preload("Reactions", orderReactions).
+		Where("id IN ?", ids).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]models.Message, len(messages))
+	for _, m := range messages {
+		byID[m.ID] = m
+	}
+
+	// Keep the ranking order from the search query
+	results := make([]models.MessageSearchResult, 0, len(rows))
+	for _, row := range rows {
+		message, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, models.MessageSearchResult{
+			Message: message,
+			Snippet: highlightSnippet(row.Snippet),
+			Rank:    row.Rank,
+		})
+	}
+
+	return results, nil
+}
+
+func (r *messageRepository) FindFlaggedByMeetingID(meetingID uuid.UUID) ([]models.Message, error) {
+	var messages []models.Message
+	err := r.db.Preload("User").Preload("Reactions", orderReactions).
+		Where("meeting_id = ? AND is_flagged = ?", meetingID, true).
+		Order("created_at DESC").
+		Find(&messages).Error
+	return messages, err
+}
+
+// StreamByMeetingID walks the meeting history in chronological order, calling fn
+// with one batch at a time so long meetings are never loaded into memory at once
+func (r *messageRepository) StreamByMeetingID(
+	meetingID, viewerID uuid.UUID,
+	batchSize int,
+	fn func([]models.Message) error,
+) error {
+	var lastCreatedAt time.Time
+	var lastID uuid.UUID
+
+	for {
+		var messages []models.Message
+		query := r.db.Preload("User").
+			Where("meeting_id = ?", meetingID).
+			Scopes(visibleTo(viewerID))
+
+		// Keyset pagination on (created_at, id) keeps each batch query cheap
+		if lastID != uuid.Nil {
+			query = query.Where("(created_at, id) > (?, ?)", lastCreatedAt, lastID)
+		}
+
+		err := query.Order("created_at ASC, id ASC").
+			Limit(batchSize).
+			Find(&messages).Error
+		if err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+
+		if err := fn(messages); err != nil {
+			return err
+		}
+		if len(messages) < batchSize {
+			return nil
+		}
+
+		last := messages[len(messages)-1]
+		lastCreatedAt, lastID = last.CreatedAt, last.ID
+	}
+}
+
 func (r *messageRepository) Update(message *models.Message) error {
 	return r.db.Save(message).Error
 }
@@ -87,3 +269,22 @@ func (r *messageRepository) CountByMeetingID(meetingID uuid.UUID) (int64, error)
 		Count(&count).Error
 	return count, err
 }
+
+// orderReactions keeps preloaded reactions in the order they were added
+func orderReactions(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at ASC")
+}
+
+// highlightSnippet escapes a search snippet and marks the matched terms
+func highlightSnippet(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, snippetStartSel, "<mark>")
+	return strings.ReplaceAll(escaped, snippetStopSel, "</mark>")
+}
+
+// visibleTo hides private messages the viewer neither sent nor received
+func visibleTo(viewerID uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(recipient_id IS NULL OR user_id = ? OR recipient_id = ?)", viewerID, viewerID)
+	}
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrganizationNotFound       = errors.New("organization not found")
+	ErrOrganizationMemberNotFound = errors.New("organization member not found")
+	ErrOrganizationMemberExists   = errors.New("user is already a member of the organization")
+)
+
+type OrganizationRepository interface {
+	Create(org *models.Organization, ownerID uuid.UUID) error
+	FindByID(id uuid.UUID) (*models.Organization, error)
+	Update(org *models.Organization) error
+	Delete(id uuid.UUID) error
+	FindMemberships(userID uuid.UUID) ([]models.OrganizationMember, error)
+	FindMember(orgID, userID uuid.UUID) (*models.OrganizationMember, error)
+	FindMembers(orgID uuid.UUID) ([]models.OrganizationMember, error)
+	AddMember(member *models.OrganizationMember) error
+	UpdateMemberRole(orgID, userID uuid.UUID, role models.OrganizationRole) error
+	RemoveMember(orgID, userID uuid.UUID) error
+	CountOwners(orgID uuid.UUID) (int64, error)
+}
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+// Create stores the organization with ownerID as its first owner
+func (r *organizationRepository) Create(org *models.Organization, ownerID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.OrganizationMember{
+			OrganizationID: org.ID,
+			UserID:         ownerID,
+			Role:           models.OrganizationRoleOwner,
+		}).Error
+	})
+}
+
+func (r *organizationRepository) FindByID(id uuid.UUID) (*models.Organization, error) {
+	var org models.Organization
+	err := r.db.Where("id = ?", id).First(&org).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *organizationRepository) Update(org *models.Organization) error {
+	return r.db.Save(org).Error
+}
+
+// Delete removes the organization and its memberships. Its meetings are
+// kept and become the hosts' own.
+func (r *organizationRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Meeting{}).
+			Where("organization_id = ?", id).
+			Updates(map[string]interface{}{
+				"organization_id": nil,
+				"visibility":      models.MeetingVisibilityPublic,
+			}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", id).Delete(&models.OrganizationMember{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.Organization{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrOrganizationNotFound
+		}
+		return nil
+	})
+}
+
+// FindMemberships returns the user's memberships with their organizations,
+// by organization name
+func (r *organizationRepository) FindMemberships(userID uuid.UUID) ([]models.OrganizationMember, error) {
+	var members []models.OrganizationMember
+	err := r.db.Joins("Organization").
+		Where("organization_members.user_id = ?", userID).
+		Order(`"Organization"."name" ASC`).
+		Find(&members).Error
+	return members, err
+}
+
+func (r *organizationRepository) FindMember(orgID, userID uuid.UUID) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	err := r.db.Joins("Organization").
+		Where("organization_members.organization_id = ? AND organization_members.user_id = ?", orgID, userID).
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationMemberNotFound
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+// FindMembers returns the organization's members with their users, oldest
+// membership first
+func (r *organizationRepository) FindMembers(orgID uuid.UUID) ([]models.OrganizationMember, error) {
+	var members []models.OrganizationMember
+	err := r.db.Joins("User").
+		Where("organization_members.organization_id = ?", orgID).
+		Order("organization_members.created_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+func (r *organizationRepository) AddMember(member *models.OrganizationMember) error {
+	_, err := r.FindMember(member.OrganizationID, member.UserID)
+	if err == nil {
+		return ErrOrganizationMemberExists
+	}
+	if err != ErrOrganizationMemberNotFound {
+		return err
+	}
+	return r.db.Create(member).Error
+}
+
+func (r *organizationRepository) UpdateMemberRole(orgID, userID uuid.UUID, role models.OrganizationRole) error {
+	result := r.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrganizationMemberNotFound
+	}
+	return nil
+}
+
+func (r *organizationRepository) RemoveMember(orgID, userID uuid.UUID) error {
+	result := r.db.Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Delete(&models.OrganizationMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrganizationMemberNotFound
+	}
+	return nil
+}
+
+func (r *organizationRepository) CountOwners(orgID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", orgID, models.OrganizationRoleOwner).
+		Count(&count).Error
+	return count, err
+}
@@ -16,13 +16,18 @@ var (
 
 type ParticipantRepository interface {
 	Create(participant *models.Participant) error
+	Rejoin(participant *models.Participant) error
 	FindByID(id uuid.UUID) (*models.Participant, error)
 	FindByMeetingID(meetingID uuid.UUID) ([]models.Participant, error)
+	FindSessionsByMeetingID(meetingID uuid.UUID) ([]models.ParticipantSession, error)
 	FindByUserAndMeeting(userID, meetingID uuid.UUID) (*models.Participant, error)
 	FindActiveMeetingParticipants(meetingID uuid.UUID) ([]models.Participant, error)
 	Update(participant *models.Participant) error
 	UpdateMediaStatus(id uuid.UUID, isMuted, isVideoOn, isSharing bool) error
+	UpdateRole(id uuid.UUID, role models.ParticipantRole) error
 	MarkAsLeft(id uuid.UUID) error
+	FindStaleActiveParticipants(joinedBefore time.Time) ([]models.Participant, error)
+	CloseParticipantsOfEndedMeetings() (int64, error)
 	Delete(id uuid.UUID) error
 	CountActiveMeetingParticipants(meetingID uuid.UUID) (int64, error)
 	IsUserInMeeting(userID, meetingID uuid.UUID) (bool, error)
@@ -46,12 +51,49 @@ func (r *participantRepository) Create(participant *models.Participant) error {
 		return ErrParticipantAlreadyExists
 	}
 
-	return r.db.Create(participant).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(participant).Error; err != nil {
+			return err
+		}
+		return openSession(tx, participant)
+	})
+}
+
+// Rejoin brings a participant who previously left back into the meeting,
+// resetting their media state and opening a new attendance session
+func (r *participantRepository) Rejoin(participant *models.Participant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Participant{}).
+			Where("id = ? AND left_at IS NOT NULL", participant.ID).
+			Updates(map[string]interface{}{
+				"left_at":     nil,
+				"is_muted":    participant.IsMuted,
+				"is_video_on": participant.IsVideoOn,
+				"is_sharing":  false,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrParticipantAlreadyExists
+		}
+		return openSession(tx, participant)
+	})
+}
+
+// openSession records the start of a new attendance session
+func openSession(tx *gorm.DB, participant *models.Participant) error {
+	return tx.Create(&models.ParticipantSession{
+		ParticipantID: participant.ID,
+		MeetingID:     participant.MeetingID,
+		UserID:        participant.UserID,
+		JoinedAt:      time.Now(),
+	}).Error
 }
 
 func (r *participantRepository) FindByID(id uuid.UUID) (*models.Participant, error) {
 	var participant models.Participant
-	err := r.db.Preload("User").Preload("Meeting").
+	err := r.db.Preload("User").Preload("Meeting").Preload("Sessions", orderSessions).
 		Where("id = ?", id).First(&participant).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -64,7 +106,7 @@ func (r *participantRepository) FindByID(id uuid.UUID) (*models.Participant, err
 
 func (r *participantRepository) FindByMeetingID(meetingID uuid.UUID) ([]models.Participant, error) {
 	var participants []models.Participant
-	err := r.db.Preload("User").
+	err := r.db.Preload("User").Preload("Sessions", orderSessions).
 		Where("meeting_id = ?", meetingID).
 		Order("joined_at ASC").
 		Find(&participants).Error
@@ -85,9 +127,22 @@ func (r *participantRepository) FindByUserAndMeeting(userID, meetingID uuid.UUID
 	return &participant, nil
 }
 
+func (r *participantRepository) FindSessionsByMeetingID(meetingID uuid.UUID) ([]models.ParticipantSession, error) {
+	var sessions []models.ParticipantSession
+	err := r.db.Where("meeting_id = ?", meetingID).
+		Order("joined_at ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// orderSessions preloads attendance sessions in the order they started
+func orderSessions(db *gorm.DB) *gorm.DB {
+	return db.Order("joined_at ASC")
+}
+
 func (r *participantRepository) FindActiveMeetingParticipants(meetingID uuid.UUID) ([]models.Participant, error) {
 	var participants []models.Participant
-	err := r.db.Preload("User").
+	err := r.db.Preload("User").Preload("Sessions", orderSessions).
 		Where("meeting_id = ? AND left_at IS NULL", meetingID).
 		Order("joined_at ASC").
 		Find(&participants).Error
@@ -108,11 +163,65 @@ func (r *participantRepository) UpdateMediaStatus(id uuid.UUID, isMuted, isVideo
 		}).Error
 }
 
-func (r *participantRepository) MarkAsLeft(id uuid.UUID) error {
-	now := time.Now()
+func (r *participantRepository) UpdateRole(id uuid.UUID, role models.ParticipantRole) error {
 	return r.db.Model(&models.Participant{}).
 		Where("id = ?", id).
-		Update("left_at", now).Error
+		Update("role", role).Error
+}
+
+// MarkAsLeft records that the participant left and closes their open session
+func (r *participantRepository) MarkAsLeft(id uuid.UUID) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Participant{}).
+			Where("id = ? AND left_at IS NULL", id).
+			Updates(map[string]interface{}{
+				"left_at":    now,
+				"is_sharing": false,
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.ParticipantSession{}).
+			Where("participant_id = ? AND left_at IS NULL", id).
+			Update("left_at", now).Error
+	})
+}
+
+// FindStaleActiveParticipants returns participants of active meetings who are
+// still marked present and have not (re)joined since joinedBefore
+func (r *participantRepository) FindStaleActiveParticipants(joinedBefore time.Time) ([]models.Participant, error) {
+	var participants []models.Participant
+	err := r.db.Preload("User").
+		Joins("JOIN meetings ON meetings.id = participants.meeting_id AND meetings.status = ?", models.MeetingStatusActive).
+		Where("participants.left_at IS NULL AND participants.joined_at < ?", joinedBefore).
+		Where("NOT EXISTS (SELECT 1 FROM participant_sessions s "+
+			"WHERE s.participant_id = participants.id AND s.left_at IS NULL AND s.joined_at >= ?)", joinedBefore).
+		Find(&participants).Error
+	return participants, err
+}
+
+// CloseParticipantsOfEndedMeetings marks participants of ended meetings as
+// left at the time the meeting ended, closing their open sessions too
+func (r *participantRepository) CloseParticipantsOfEndedMeetings() (int64, error) {
+	var closed int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE participant_sessions AS s
+			SET left_at = GREATEST(s.joined_at, COALESCE(m.ended_at, NOW()))
+			FROM meetings AS m
+			WHERE s.meeting_id = m.id AND m.status = ? AND s.left_at IS NULL`,
+			models.MeetingStatusEnded).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`UPDATE participants AS p
+			SET left_at = GREATEST(p.joined_at, COALESCE(m.ended_at, NOW())), is_sharing = FALSE, updated_at = NOW()
+			FROM meetings AS m
+			WHERE p.meeting_id = m.id AND m.status = ? AND p.left_at IS NULL AND p.deleted_at IS NULL`,
+			models.MeetingStatusEnded)
+		closed = result.RowsAffected
+		return result.Error
+	})
+	return closed, err
 }
 
 func (r *participantRepository) Delete(id uuid.UUID) error {
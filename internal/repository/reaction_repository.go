@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReactionNotFound      = errors.New("reaction not found")
+	ErrReactionAlreadyExists = errors.New("reaction already exists")
+)
+
+type ReactionRepository interface {
+	Create(reaction *models.MessageReaction) error
+	FindByMessageID(messageID uuid.UUID) ([]models.MessageReaction, error)
+	Delete(messageID, userID uuid.UUID, emoji string) error
+}
+
+type reactionRepository struct {
+	db *gorm.DB
+}
+
+func NewReactionRepository(db *gorm.DB) ReactionRepository {
+	return &reactionRepository{db: db}
+}
+
+func (r *reactionRepository) Create(reaction *models.MessageReaction) error {
+	// Each user may only react once with the same emoji
+	err := r.db.Create(reaction).Error
+	if isUniqueViolation(err) {
+		return ErrReactionAlreadyExists
+	}
+	return err
+}
+
+func (r *reactionRepository) FindByMessageID(messageID uuid.UUID) ([]models.MessageReaction, error) {
+	var reactions []models.MessageReaction
+	err := r.db.Where("message_id = ?", messageID).
+		Order("created_at ASC").
+		Find(&reactions).Error
+	return reactions, err
+}
+
+func (r *reactionRepository) Delete(messageID, userID uuid.UUID, emoji string) error {
+	result := r.db.
+		Where("message_id = ? AND user_id = ? AND emoji = ?", messageID, userID, emoji).
+		Delete(&models.MessageReaction{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReactionNotFound
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/meet-app/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var ErrIdentityNotFound = errors.New("identity not found")
+
+type UserIdentityRepository interface {
+	Create(identity *models.UserIdentity) error
+	FindBySubject(issuer, subject string) (*models.UserIdentity, error)
+	Update(identity *models.UserIdentity) error
+}
+
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+func (r *userIdentityRepository) Create(identity *models.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+func (r *userIdentityRepository) FindBySubject(issuer, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.db.Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrIdentityNotFound
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *userIdentityRepository) Update(identity *models.UserIdentity) error {
+	return r.db.Save(identity).Error
+}
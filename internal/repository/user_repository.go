@@ -2,6 +2,8 @@ package repository
 
 import (
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/meet-app/backend/internal/models"
@@ -15,6 +17,16 @@ var (
 	ErrUsernameAlreadyExists = errors.New("username already exists")
 )
 
+// UserListParams holds the filters for listing users
+type UserListParams struct {
+	// Query matches email, username or name
+	Query     string
+	Role      models.UserRole
+	Suspended *bool
+	Offset    int
+	Limit     int
+}
+
 type UserRepository interface {
 	Create(user *models.User) error
 	FindByID(id uuid.UUID) (*models.User, error)
@@ -24,6 +36,10 @@ type UserRepository interface {
 	Delete(id uuid.UUID) error
 	ExistsByEmail(email string) (bool, error)
 	ExistsByUsername(username string) (bool, error)
+	FindServiceAccountsByOwner(ownerID uuid.UUID) ([]models.User, error)
+	AdvanceTOTPStep(id uuid.UUID, step int64) (bool, error)
+	List(params UserListParams) ([]models.User, error)
+	DeleteUnadmittedGuests(createdBefore time.Time) (int64, error)
 }
 
 type userRepository struct {
@@ -111,3 +127,59 @@ func (r *userRepository) ExistsByUsername(username string) (bool, error) {
 	err := r.db.Model(&models.User{}).Where("username = ?", username).Count(&count).Error
 	return count > 0, err
 }
+
+func (r *userRepository) FindServiceAccountsByOwner(ownerID uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("owner_id = ? AND is_service_account", ownerID).
+		Order("created_at DESC").
+		Find(&users).Error
+	return users, err
+}
+
+// AdvanceTOTPStep records step as the user's last accepted TOTP step. It
+// reports false when that step or a later one was already used, so each
+// code is accepted once even by concurrent requests.
+func (r *userRepository) AdvanceTOTPStep(id uuid.UUID, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteUnadmittedGuests removes guests created before the cutoff that never
+// became a participant of any meeting. Admitted guests are kept for the
+// attendance and chat history they are part of.
+func (r *userRepository) DeleteUnadmittedGuests(createdBefore time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("is_guest AND created_at < ?", createdBefore).
+		Where("NOT EXISTS (SELECT 1 FROM participants WHERE participants.user_id = users.id)").
+		Delete(&models.User{})
+	return result.RowsAffected, result.Error
+}
+
+// List returns one page of users matching the filters, newest first
+func (r *userRepository) List(params UserListParams) ([]models.User, error) {
+	query := r.db.Model(&models.User{})
+
+	if q := strings.TrimSpace(params.Query); q != "" {
+		pattern := "%" + escapeLike(q) + "%"
+		query = query.Where("(email ILIKE ? OR username ILIKE ? OR name ILIKE ?)", pattern, pattern, pattern)
+	}
+	if params.Role != "" {
+		query = query.Where("role = ?", params.Role)
+	}
+	if params.Suspended != nil {
+		if *params.Suspended {
+			query = query.Where("suspended_at IS NOT NULL")
+		} else {
+			query = query.Where("suspended_at IS NULL")
+		}
+	}
+
+	var users []models.User
+	err := query.Order("created_at DESC, id DESC").
+		Offset(params.Offset).
+		Limit(params.Limit).
+		Find(&users).Error
+	return users, err
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrUserTokenInvalid = errors.New("token is invalid, expired or already used")
+
+type UserTokenRepository interface {
+	Create(token *models.UserToken) error
+	Consume(purpose models.UserTokenPurpose, tokenHash string) (*models.UserToken, error)
+	InvalidateForUser(userID uuid.UUID, purpose models.UserTokenPurpose) error
+}
+
+type userTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+func (r *userTokenRepository) Create(token *models.UserToken) error {
+	return r.db.Create(token).Error
+}
+
+// Consume marks an unused, unexpired token as used and returns it. A token
+// can be consumed only once, even by concurrent requests.
+func (r *userTokenRepository) Consume(purpose models.UserTokenPurpose, tokenHash string) (*models.UserToken, error) {
+	var tokens []models.UserToken
+	now := time.Now()
+
+	result := r.db.Model(&tokens).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(tokens) == 0 {
+		return nil, ErrUserTokenInvalid
+	}
+	return &tokens[0], nil
+}
+
+// InvalidateForUser uses up the outstanding tokens of a user for a purpose,
+// e.g. older reset links once a new one is sent
+func (r *userTokenRepository) InvalidateForUser(userID uuid.UUID, purpose models.UserTokenPurpose) error {
+	return r.db.Model(&models.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
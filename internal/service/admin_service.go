@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/pkg/auth"
+)
+
+var (
+	ErrCannotModerateSelf      = errors.New("admins cannot suspend or delete their own account")
+	ErrInvalidSuspensionReason = errors.New("suspension reason must be at most 500 characters")
+)
+
+const (
+	// Default and maximum page size of the admin listings
+	defaultAdminListLimit = 50
+	maxAdminListLimit     = 200
+
+	maxSuspensionReasonLength = 500
+)
+
+// SignalingConnections is the WebSocket side of the admin view: it counts a
+// meeting's connections and disconnects users
+type SignalingConnections interface {
+	GetClientsInMeeting(meetingID uuid.UUID) int
+	DisconnectUser(userID uuid.UUID)
+}
+
+// EventStreamConnections counts the event stream connections of a meeting
+// and closes those of a user
+type EventStreamConnections interface {
+	GetClientCount(meetingID uuid.UUID) int
+	DisconnectUser(userID uuid.UUID)
+}
+
+// ActiveMeeting is a live meeting with its open real-time connections
+type ActiveMeeting struct {
+	Meeting              models.Meeting
+	SignalingConnections int
+	EventStreams         int
+}
+
+type AdminService interface {
+	IsAdmin(userID uuid.UUID) (bool, error)
+	ListUsers(params repository.UserListParams) ([]models.User, error)
+	GetUser(userID uuid.UUID) (*models.User, error)
+	SuspendUser(adminID, userID uuid.UUID, reason string, client ClientInfo) (*models.User, error)
+	UnsuspendUser(adminID, userID uuid.UUID, client ClientInfo) (*models.User, error)
+	DeleteUser(adminID, userID uuid.UUID, client ClientInfo) error
+	ListActiveMeetings() ([]ActiveMeeting, error)
+	EndMeeting(adminID, meetingID uuid.UUID, client ClientInfo) (*models.Meeting, error)
+	ListLoginAttempts(params repository.LoginAttemptListParams) ([]models.LoginAttempt, error)
+}
+
+type adminService struct {
+	userRepo       repository.UserRepository
+	tokenRepo      repository.APITokenRepository
+	loginRepo      repository.LoginAttemptRepository
+	meetingService MeetingService
+	audit          AuditService
+	revocations    *auth.RevocationStore
+	signaling      SignalingConnections
+	eventStreams   EventStreamConnections
+}
+
+func NewAdminService(
+	userRepo repository.UserRepository,
+	tokenRepo repository.APITokenRepository,
+	loginRepo repository.LoginAttemptRepository,
+	meetingService MeetingService,
+	audit AuditService,
+	revocations *auth.RevocationStore,
+	signaling SignalingConnections,
+	eventStreams EventStreamConnections,
+) AdminService {
+	return &adminService{
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		loginRepo:      loginRepo,
+		meetingService: meetingService,
+		audit:          audit,
+		revocations:    revocations,
+		signaling:      signaling,
+		eventStreams:   eventStreams,
+	}
+}
+
+// IsAdmin reports whether the user currently has the admin role. It is read
+// from the database so demoting an admin takes effect immediately.
+func (s *adminService) IsAdmin(userID uuid.UUID) (bool, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return user.Role == models.UserRoleAdmin && !user.IsSuspended(), nil
+}
+
+// ListUsers returns one page of users, newest first
+func (s *adminService) ListUsers(params repository.UserListParams) ([]models.User, error) {
+	if params.Role != "" && !params.Role.IsValid() {
+		return nil, ErrInvalidListFilter
+	}
+	params.Offset, params.Limit = adminPage(params.Offset, params.Limit)
+	return s.userRepo.List(params)
+}
+
+func (s *adminService) GetUser(userID uuid.UUID) (*models.User, error) {
+	return s.userRepo.FindByID(userID)
+}
+
+// SuspendUser blocks the user from logging in, revokes their sessions and
+// API tokens and closes their connections. The tokens of service accounts
+// they own stop working too.
+func (s *adminService) SuspendUser(adminID, userID uuid.UUID, reason string, client ClientInfo) (*models.User, error) {
+	if adminID == userID {
+		return nil, ErrCannotModerateSelf
+	}
+	reason = strings.TrimSpace(reason)
+	if len([]rune(reason)) > maxSuspensionReasonLength {
+		return nil, ErrInvalidSuspensionReason
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if user.SuspendedAt == nil {
+		user.SuspendedAt = &now
+	}
+	user.SuspensionReason = reason
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.signOut(user.ID, now); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(&models.AuditEvent{
+		Action:       models.AuditActionUserSuspended,
+		ActorID:      &adminID,
+		TargetUserID: &user.ID,
+		Payload:      map[string]interface{}{"reason": reason},
+	}, client)
+	return user, nil
+}
+
+// UnsuspendUser lets the user log in again. Sessions revoked by the
+// suspension stay revoked.
+func (s *adminService) UnsuspendUser(adminID, userID uuid.UUID, client ClientInfo) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.SuspendedAt = nil
+	user.SuspensionReason = ""
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(&models.AuditEvent{
+		Action:       models.AuditActionUserUnsuspended,
+		ActorID:      &adminID,
+		TargetUserID: &user.ID,
+	}, client)
+	return user, nil
+}
+
+// DeleteUser signs the user out everywhere and deletes the account. Meetings
+// they hosted are kept.
+func (s *adminService) DeleteUser(adminID, userID uuid.UUID, client ClientInfo) error {
+	if adminID == userID {
+		return ErrCannotModerateSelf
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.signOut(userID, time.Now()); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.RevokeAllForUser(userID); err != nil {
+		return err
+	}
+	if err := s.userRepo.Delete(userID); err != nil {
+		return err
+	}
+
+	s.audit.Record(&models.AuditEvent{
+		Action:       models.AuditActionUserDeleted,
+		ActorID:      &adminID,
+		TargetUserID: &userID,
+		Payload:      map[string]interface{}{"email": user.Email, "username": user.Username},
+	}, client)
+	return nil
+}
+
+// signOut revokes the sessions issued to the user before now and drops
+// their real-time connections
+func (s *adminService) signOut(userID uuid.UUID, now time.Time) error {
+	if s.revocations != nil {
+		if err := s.revocations.RevokeBefore(context.Background(), userID, now); err != nil {
+			return err
+		}
+	}
+	if s.signaling != nil {
+		s.signaling.DisconnectUser(userID)
+	}
+	if s.eventStreams != nil {
+		s.eventStreams.DisconnectUser(userID)
+	}
+	return nil
+}
+
+// ListActiveMeetings returns the active meetings with their open WebSocket
+// and event stream connections on this server
+func (s *adminService) ListActiveMeetings() ([]ActiveMeeting, error) {
+	meetings, err := s.meetingService.GetActiveMeetings()
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]ActiveMeeting, len(meetings))
+	for i, meeting := range meetings {
+		active[i] = ActiveMeeting{Meeting: meeting}
+		if s.signaling != nil {
+			active[i].SignalingConnections = s.signaling.GetClientsInMeeting(meeting.ID)
+		}
+		if s.eventStreams != nil {
+			active[i].EventStreams = s.eventStreams.GetClientCount(meeting.ID)
+		}
+	}
+	return active, nil
+}
+
+// EndMeeting ends any meeting regardless of its host and returns it
+func (s *adminService) EndMeeting(adminID, meetingID uuid.UUID, client ClientInfo) (*models.Meeting, error) {
+	meeting, err := s.meetingService.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.meetingService.AutoEndMeeting(meetingID, &adminID, "admin", client); err != nil {
+		return nil, err
+	}
+	return meeting, nil
+}
+
+// ListLoginAttempts returns one page of the login audit log, newest first
+func (s *adminService) ListLoginAttempts(params repository.LoginAttemptListParams) ([]models.LoginAttempt, error) {
+	params.Email = strings.TrimSpace(params.Email)
+	params.IPAddress = strings.TrimSpace(params.IPAddress)
+	params.Offset, params.Limit = adminPage(params.Offset, params.Limit)
+	return s.loginRepo.List(params)
+}
+
+// adminPage clamps the paging of an admin listing
+func adminPage(offset, limit int) (int, int) {
+	if limit <= 0 {
+		limit = defaultAdminListLimit
+	}
+	if limit > maxAdminListLimit {
+		limit = maxAdminListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset, limit
+}
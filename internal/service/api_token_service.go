@@ -0,0 +1,213 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/pkg/auth"
+)
+
+var (
+	ErrInvalidTokenName       = errors.New("token name must be between 1 and 100 characters")
+	ErrInvalidScopes          = errors.New("at least one scope is required and all scopes must be known")
+	ErrInvalidTokenExpiry     = errors.New("token expiry must not be negative")
+	ErrServiceAccountNotFound = errors.New("service account not found")
+)
+
+const (
+	maxTokenNameLength = 100
+
+	// Characters of a token kept to tell tokens apart in listings
+	apiTokenDisplayPrefix = 12
+
+	// Last-used times are written at most this often per token
+	apiTokenLastUsedResolution = time.Minute
+)
+
+type APITokenService interface {
+	CreateToken(callerID, userID uuid.UUID, name string, scopes []models.APIScope, expiresIn time.Duration) (*models.APIToken, string, error)
+	ListTokens(callerID, userID uuid.UUID) ([]models.APIToken, error)
+	RevokeToken(callerID, userID, tokenID uuid.UUID) error
+	VerifyAPIToken(token string) (*models.APIToken, error)
+}
+
+type apiTokenService struct {
+	tokenRepo repository.APITokenRepository
+	userRepo  repository.UserRepository
+}
+
+func NewAPITokenService(tokenRepo repository.APITokenRepository, userRepo repository.UserRepository) APITokenService {
+	return &apiTokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// CreateToken creates a token for the caller or one of their service
+// accounts and returns it with the plaintext token, which is not stored.
+// A zero expiresIn creates a token that does not expire.
+func (s *apiTokenService) CreateToken(
+	callerID, userID uuid.UUID,
+	name string,
+	scopes []models.APIScope,
+	expiresIn time.Duration,
+) (*models.APIToken, string, error) {
+	if err := s.authorize(callerID, userID); err != nil {
+		return nil, "", err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxTokenNameLength {
+		return nil, "", ErrInvalidTokenName
+	}
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if expiresIn < 0 {
+		return nil, "", ErrInvalidTokenExpiry
+	}
+
+	plaintext, hash, err := auth.GenerateAPIToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := &models.APIToken{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    plaintext[:apiTokenDisplayPrefix],
+		TokenHash: hash,
+		Scopes:    scopes,
+	}
+	if expiresIn > 0 {
+		expiresAt := time.Now().Add(expiresIn)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, "", err
+	}
+	return token, plaintext, nil
+}
+
+// ListTokens returns the unrevoked tokens of the caller or one of their
+// service accounts
+func (s *apiTokenService) ListTokens(callerID, userID uuid.UUID) ([]models.APIToken, error) {
+	if err := s.authorize(callerID, userID); err != nil {
+		return nil, err
+	}
+	return s.tokenRepo.FindByUserID(userID)
+}
+
+// RevokeToken revokes a token of the caller or one of their service accounts
+func (s *apiTokenService) RevokeToken(callerID, userID, tokenID uuid.UUID) error {
+	if err := s.authorize(callerID, userID); err != nil {
+		return err
+	}
+	return s.tokenRepo.Revoke(tokenID, userID)
+}
+
+// VerifyAPIToken returns the usable token with its user. Invalid and revoked
+// tokens yield auth.ErrInvalidToken and expired ones auth.ErrExpiredToken.
+func (s *apiTokenService) VerifyAPIToken(plaintext string) (*models.APIToken, error) {
+	if !strings.HasPrefix(plaintext, auth.APITokenPrefix) {
+		return nil, auth.ErrInvalidToken
+	}
+
+	token, err := s.tokenRepo.FindByHash(auth.HashOpaqueToken(plaintext))
+	if err != nil {
+		if err == repository.ErrAPITokenNotFound {
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if token.RevokedAt != nil {
+		return nil, auth.ErrInvalidToken
+	}
+	if !token.IsUsable(now) {
+		return nil, auth.ErrExpiredToken
+	}
+	if err := s.checkNotSuspended(&token.User); err != nil {
+		return nil, err
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= apiTokenLastUsedResolution {
+		if err := s.tokenRepo.TouchLastUsed(token.ID, now); err != nil {
+			log.Printf("API tokens: failed to record use of %s: %v", token.ID, err)
+		} else {
+			token.LastUsedAt = &now
+		}
+	}
+	return token, nil
+}
+
+// checkNotSuspended rejects tokens of suspended users, and of service
+// accounts whose owner is suspended or deleted
+func (s *apiTokenService) checkNotSuspended(user *models.User) error {
+	if user.IsSuspended() {
+		return auth.ErrInvalidToken
+	}
+	if !user.IsServiceAccount || user.OwnerID == nil {
+		return nil
+	}
+
+	owner, err := s.userRepo.FindByID(*user.OwnerID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return auth.ErrInvalidToken
+		}
+		return err
+	}
+	if owner.IsSuspended() {
+		return auth.ErrInvalidToken
+	}
+	return nil
+}
+
+// authorize allows callers to manage their own tokens and those of the
+// service accounts they own
+func (s *apiTokenService) authorize(callerID, userID uuid.UUID) error {
+	if callerID == userID {
+		return nil
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return ErrServiceAccountNotFound
+		}
+		return err
+	}
+	if !user.IsServiceAccount || user.OwnerID == nil || *user.OwnerID != callerID {
+		return ErrServiceAccountNotFound
+	}
+	return nil
+}
+
+// normalizeScopes validates the scopes and removes duplicates
+func normalizeScopes(scopes []models.APIScope) ([]models.APIScope, error) {
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScopes
+	}
+
+	seen := make(map[models.APIScope]bool, len(scopes))
+	normalized := make([]models.APIScope, 0, len(scopes))
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, ErrInvalidScopes
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/config"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+)
+
+const (
+	// How often audit events past the retention window are removed
+	auditPruneInterval = time.Hour
+
+	maxRequestIDLength = 64
+)
+
+// ClientInfo describes the client a request came from, for the login and
+// audit logs
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+	RequestID string
+}
+
+type AuditService interface {
+	Record(event *models.AuditEvent, client ClientInfo)
+	ListMeetingEvents(meetingID, userID uuid.UUID, params repository.AuditEventListParams) ([]models.AuditEvent, error)
+	ListEvents(params repository.AuditEventListParams) ([]models.AuditEvent, error)
+	RunRetention(ctx context.Context)
+}
+
+type auditService struct {
+	auditRepo   repository.AuditEventRepository
+	meetingRepo repository.MeetingRepository
+	cfg         *config.AuditConfig
+}
+
+func NewAuditService(
+	auditRepo repository.AuditEventRepository,
+	meetingRepo repository.MeetingRepository,
+	cfg *config.AuditConfig,
+) AuditService {
+	return &auditService{
+		auditRepo:   auditRepo,
+		meetingRepo: meetingRepo,
+		cfg:         cfg,
+	}
+}
+
+// Record stores the event with the client it came from; a failure to do so
+// does not fail the audited action
+func (s *auditService) Record(event *models.AuditEvent, client ClientInfo) {
+	event.IPAddress = client.IPAddress
+	event.RequestID = truncate(client.RequestID, maxRequestIDLength)
+	if err := s.auditRepo.Create(event); err != nil {
+		log.Printf("Audit: failed to record %s event: %v", event.Action, err)
+	}
+}
+
+// ListMeetingEvents returns one page of the meeting's audit events, newest
+// first (host only)
+func (s *auditService) ListMeetingEvents(meetingID, userID uuid.UUID, params repository.AuditEventListParams) ([]models.AuditEvent, error) {
+	meeting, err := s.meetingRepo.FindByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if meeting.HostID != userID {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	params.MeetingID = &meetingID
+	return s.ListEvents(params)
+}
+
+// ListEvents returns one page of audit events matching the filters, newest
+// first
+func (s *auditService) ListEvents(params repository.AuditEventListParams) ([]models.AuditEvent, error) {
+	params.Offset, params.Limit = adminPage(params.Offset, params.Limit)
+	return s.auditRepo.List(params)
+}
+
+// RunRetention removes audit events older than the retention window until
+// the context is done. Every server may run it; removing the same events
+// twice is harmless.
+func (s *auditService) RunRetention(ctx context.Context) {
+	if s.cfg.RetentionDays <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(auditPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		s.prune()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *auditService) prune() {
+	cutoff := time.Now().AddDate(0, 0, -s.cfg.RetentionDays)
+	removed, err := s.auditRepo.DeleteBefore(cutoff)
+	if err != nil {
+		log.Printf("Audit: failed to remove expired events: %v", err)
+		return
+	}
+	if removed > 0 {
+		log.Printf("Audit: removed %d events older than %d days", removed, s.cfg.RetentionDays)
+	}
+}
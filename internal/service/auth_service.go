@@ -1,40 +1,107 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/meet-app/backend/internal/config"
+	"github.com/meet-app/backend/internal/email"
 	"github.com/meet-app/backend/internal/models"
 	"github.com/meet-app/backend/internal/repository"
 	"github.com/meet-app/backend/pkg/auth"
+	"github.com/meet-app/backend/pkg/mail"
 )
 
 var (
 	ErrInvalidCredentials = errors.New("invalid email or password")
 	ErrWeakPassword       = errors.New("password must be at least 8 characters")
+	ErrInvalidUserToken   = errors.New("link is invalid, expired or already used")
+	ErrAlreadyVerified    = errors.New("email address is already verified")
+	ErrAccountSuspended   = errors.New("account is suspended")
 )
 
+const (
+	// Time allowed to deliver a single email
+	mailSendTimeout = 30 * time.Second
+
+	// Time allowed for the throttling checks of a single login
+	loginCheckTimeout = 5 * time.Second
+
+	maxUserAgentLength = 512
+)
+
+// dummyPasswordHash is compared against when no account matches a login so
+// unknown emails take as long to refuse as wrong passwords
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := auth.HashPassword("not-a-real-password")
+	return hash
+})
+
 type AuthService interface {
-	Register(email, username, password, name string) (*models.User, *auth.TokenPair, error)
-	Login(email, password string) (*models.User, *auth.TokenPair, error)
+	Register(email, username, password, name string, client ClientInfo) (*models.User, *auth.TokenPair, error)
+	Login(email, password string, client ClientInfo) (*models.User, *auth.TokenPair, error)
+	CompleteMFALogin(challengeToken, code string, client ClientInfo) (*models.User, *auth.TokenPair, error)
 	RefreshToken(refreshToken string) (*auth.TokenPair, error)
 	GetUserByID(id uuid.UUID) (*models.User, error)
+	ResendVerificationEmail(userID uuid.UUID) error
+	VerifyEmail(token string) (*models.User, error)
+	ForgotPassword(email string, client ClientInfo) error
+	ResetPassword(token, newPassword string) error
 }
 
 type authService struct {
-	userRepo repository.UserRepository
-	jwtCfg   *config.JWTConfig
+	userRepo    repository.UserRepository
+	tokenRepo   repository.UserTokenRepository
+	loginRepo   repository.LoginAttemptRepository
+	revocations *auth.RevocationStore
+	limiter     *LoginLimiter
+	mfa         MFAService
+	challenges  *MFAChallengeStore
+	audit       AuditService
+	mailer      mail.Mailer
+	accountCfg  *config.AccountConfig
+	keys        *auth.Keyring
+	jwtCfg      *config.JWTConfig
 }
 
-func NewAuthService(userRepo repository.UserRepository, jwtCfg *config.JWTConfig) AuthService {
+func NewAuthService(
+	userRepo repository.UserRepository,
+	tokenRepo repository.UserTokenRepository,
+	loginRepo repository.LoginAttemptRepository,
+	revocations *auth.RevocationStore,
+	limiter *LoginLimiter,
+	mfa MFAService,
+	challenges *MFAChallengeStore,
+	audit AuditService,
+	mailer mail.Mailer,
+	accountCfg *config.AccountConfig,
+	keys *auth.Keyring,
+	jwtCfg *config.JWTConfig,
+) AuthService {
 	return &authService{
-		userRepo: userRepo,
-		jwtCfg:   jwtCfg,
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		loginRepo:   loginRepo,
+		revocations: revocations,
+		limiter:     limiter,
+		mfa:         mfa,
+		challenges:  challenges,
+		audit:       audit,
+		mailer:      mailer,
+		accountCfg:  accountCfg,
+		keys:        keys,
+		jwtCfg:      jwtCfg,
 	}
 }
 
-func (s *authService) Register(email, username, password, name string) (*models.User, *auth.TokenPair, error) {
+func (s *authService) Register(email, username, password, name string, client ClientInfo) (*models.User, *auth.TokenPair, error) {
 	// Validate password strength
 	if !auth.IsPasswordValid(password) {
 		return nil, nil, ErrWeakPassword
@@ -52,14 +119,25 @@ func (s *authService) Register(email, username, password, name string) (*models.
 		Username: username,
 		Password: hashedPassword,
 		Name:     name,
+		Role:     models.UserRoleMember,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
 		return nil, nil, err
 	}
 
+	if err := s.sendVerificationEmail(user); err != nil {
+		log.Printf("Auth: failed to send verification email to %s: %v", user.ID, err)
+	}
+
+	s.audit.Record(&models.AuditEvent{
+		Action:  models.AuditActionRegister,
+		ActorID: &user.ID,
+		Payload: map[string]interface{}{"email": user.Email, "username": user.Username},
+	}, client)
+
 	// Generate tokens
-	tokens, err := auth.GenerateTokenPair(user.ID, user.Email, user.Username, s.jwtCfg)
+	tokens, err := auth.GenerateTokenPair(user.ID, user.Email, user.Username, s.keys, s.jwtCfg)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -67,23 +145,149 @@ func (s *authService) Register(email, username, password, name string) (*models.
 	return user, tokens, nil
 }
 
-func (s *authService) Login(email, password string) (*models.User, *auth.TokenPair, error) {
+// Login checks the credentials unless the account or IP address is
+// throttled, and records the attempt. Accounts with two-factor
+// authentication get an *MFARequiredError to complete with CompleteMFALogin.
+func (s *authService) Login(email, password string, client ClientInfo) (*models.User, *auth.TokenPair, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loginCheckTimeout)
+	defer cancel()
+
 	// Find user by email
 	user, err := s.userRepo.FindByEmail(email)
+	if err != nil && err != repository.ErrUserNotFound {
+		return nil, nil, err
+	}
+	attempt := &models.LoginAttempt{
+		Email:     email,
+		IPAddress: client.IPAddress,
+		UserAgent: truncate(client.UserAgent, maxUserAgentLength),
+		Method:    models.LoginMethodPassword,
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+
+	retryAfter, err := s.limiter.Wait(ctx, email, client.IPAddress)
 	if err != nil {
-		if err == repository.ErrUserNotFound {
-			return nil, nil, ErrInvalidCredentials
-		}
 		return nil, nil, err
 	}
+	if retryAfter > 0 {
+		attempt.FailureReason = models.LoginFailureThrottled
+		s.recordLogin(attempt, client)
+		return nil, nil, &LoginThrottledError{RetryAfter: retryAfter}
+	}
+
+	// Guests and service accounts have no credentials to log in with
+	if user == nil || user.IsGuest || user.IsServiceAccount {
+		auth.VerifyPassword(dummyPasswordHash(), password)
+		return nil, nil, s.failLogin(ctx, attempt, client)
+	}
 
 	// Verify password
 	if err := auth.VerifyPassword(user.Password, password); err != nil {
-		return nil, nil, ErrInvalidCredentials
+		return nil, nil, s.failLogin(ctx, attempt, client)
+	}
+
+	// Only tell suspended users so once they proved who they are
+	if user.IsSuspended() {
+		attempt.FailureReason = models.LoginFailureSuspended
+		s.recordLogin(attempt, client)
+		return nil, nil, ErrAccountSuspended
+	}
+
+	// Failures stay counted until the second factor is verified too
+	if user.IsMFAEnabled() {
+		challenge, err := s.challenges.Create(ctx, user.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		attempt.FailureReason = models.LoginFailureMFARequired
+		s.recordLogin(attempt, client)
+		return nil, nil, challenge
+	}
+
+	return s.succeedLogin(ctx, user, attempt, client)
+}
+
+// CompleteMFALogin finishes a login that Login answered with an
+// *MFARequiredError, given a TOTP or recovery code. Wrong codes count as
+// failed logins.
+func (s *authService) CompleteMFALogin(challengeToken, code string, client ClientInfo) (*models.User, *auth.TokenPair, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loginCheckTimeout)
+	defer cancel()
+
+	userID, err := s.challenges.Lookup(ctx, challengeToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return nil, nil, ErrInvalidMFAChallenge
+		}
+		return nil, nil, err
+	}
+	attempt := &models.LoginAttempt{
+		UserID:    &user.ID,
+		Email:     user.Email,
+		IPAddress: client.IPAddress,
+		UserAgent: truncate(client.UserAgent, maxUserAgentLength),
+		Method:    models.LoginMethodPassword,
+	}
+
+	// The account may have been suspended since the password was checked
+	if user.IsSuspended() {
+		attempt.FailureReason = models.LoginFailureSuspended
+		s.recordLogin(attempt, client)
+		if err := s.challenges.Delete(ctx, challengeToken); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrAccountSuspended
+	}
+
+	retryAfter, err := s.limiter.Wait(ctx, user.Email, client.IPAddress)
+	if err != nil {
+		return nil, nil, err
 	}
+	if retryAfter > 0 {
+		attempt.FailureReason = models.LoginFailureThrottled
+		s.recordLogin(attempt, client)
+		return nil, nil, &LoginThrottledError{RetryAfter: retryAfter}
+	}
+
+	if err := s.mfa.VerifyCode(user, code); err != nil {
+		if err != ErrInvalidMFACode && err != ErrMFANotEnabled {
+			return nil, nil, err
+		}
+
+		attempt.FailureReason = models.LoginFailureInvalidMFACode
+		s.recordLogin(attempt, client)
+		if err := s.challenges.Fail(ctx, challengeToken); err != nil {
+			return nil, nil, err
+		}
+		if err := s.limiter.Fail(ctx, user.Email, client.IPAddress); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrInvalidMFACode
+	}
+
+	if err := s.challenges.Delete(ctx, challengeToken); err != nil {
+		return nil, nil, err
+	}
+	return s.succeedLogin(ctx, user, attempt, client)
+}
+
+// succeedLogin clears the account's failures, records the login and issues
+// session tokens
+func (s *authService) succeedLogin(ctx context.Context, user *models.User, attempt *models.LoginAttempt, client ClientInfo) (*models.User, *auth.TokenPair, error) {
+	if err := s.limiter.Succeed(ctx, attempt.Email); err != nil {
+		return nil, nil, err
+	}
+	attempt.Success = true
+	s.recordLogin(attempt, client)
 
 	// Generate tokens
-	tokens, err := auth.GenerateTokenPair(user.ID, user.Email, user.Username, s.jwtCfg)
+	tokens, err := auth.GenerateTokenPair(user.ID, user.Email, user.Username, s.keys, s.jwtCfg)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -91,10 +295,281 @@ func (s *authService) Login(email, password string) (*models.User, *auth.TokenPa
 	return user, tokens, nil
 }
 
+// failLogin counts a failed login against the account and IP address
+func (s *authService) failLogin(ctx context.Context, attempt *models.LoginAttempt, client ClientInfo) error {
+	attempt.FailureReason = models.LoginFailureInvalidCredentials
+	s.recordLogin(attempt, client)
+
+	if err := s.limiter.Fail(ctx, attempt.Email, attempt.IPAddress); err != nil {
+		return err
+	}
+	return ErrInvalidCredentials
+}
+
+// recordLogin stores the audit records; a failure to do so does not fail the
+// login
+func (s *authService) recordLogin(attempt *models.LoginAttempt, client ClientInfo) {
+	if err := s.loginRepo.Create(attempt); err != nil {
+		log.Printf("Auth: failed to record login attempt for %s: %v", attempt.Email, err)
+	}
+	if event := loginAuditEvent(attempt); event != nil {
+		s.audit.Record(event, client)
+	}
+}
+
+// loginAuditEvent describes a login attempt for the audit log. Attempts
+// still waiting for a second factor are left out; their outcome is recorded
+// once the code is checked.
+func loginAuditEvent(attempt *models.LoginAttempt) *models.AuditEvent {
+	if attempt.FailureReason == models.LoginFailureMFARequired {
+		return nil
+	}
+
+	event := &models.AuditEvent{
+		Action:  models.AuditActionLogin,
+		ActorID: attempt.UserID,
+		Payload: map[string]interface{}{
+			"email":      attempt.Email,
+			"method":     attempt.Method,
+			"user_agent": attempt.UserAgent,
+		},
+	}
+	if !attempt.Success {
+		event.Action = models.AuditActionLoginFailed
+		event.Payload["failure_reason"] = attempt.FailureReason
+	}
+	return event
+}
+
 func (s *authService) RefreshToken(refreshToken string) (*auth.TokenPair, error) {
-	return auth.RefreshAccessToken(refreshToken, s.jwtCfg)
+	claims, err := auth.ValidateToken(refreshToken, s.keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refresh tokens of revoked sessions cannot be exchanged either
+	if s.revocations != nil {
+		revoked, err := s.revocations.IsRevoked(context.Background(), claims)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, auth.ErrInvalidToken
+		}
+	}
+
+	return auth.RefreshAccessToken(refreshToken, s.keys, s.jwtCfg)
 }
 
 func (s *authService) GetUserByID(id uuid.UUID) (*models.User, error) {
 	return s.userRepo.FindByID(id)
 }
+
+// ResendVerificationEmail sends a new verification link, invalidating
+// earlier ones
+func (s *authService) ResendVerificationEmail(userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.IsEmailVerified() {
+		return ErrAlreadyVerified
+	}
+
+	return s.sendVerificationEmail(user)
+}
+
+// VerifyEmail redeems a verification token and marks the address verified
+func (s *authService) VerifyEmail(token string) (*models.User, error) {
+	userToken, err := s.tokenRepo.Consume(models.UserTokenVerifyEmail, auth.HashOpaqueToken(token))
+	if err != nil {
+		if err == repository.ErrUserTokenInvalid {
+			return nil, ErrInvalidUserToken
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(userToken.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// ForgotPassword emails a password reset link if an account uses the
+// address. It reports success either way so addresses cannot be probed.
+// Requests are throttled per address and IP address like logins.
+func (s *authService) ForgotPassword(emailAddress string, client ClientInfo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), loginCheckTimeout)
+	defer cancel()
+
+	retryAfter, err := s.limiter.Wait(ctx, emailAddress, client.IPAddress)
+	if err != nil {
+		return err
+	}
+	if retryAfter > 0 {
+		return &LoginThrottledError{RetryAfter: retryAfter}
+	}
+	if err := s.limiter.Throttle(ctx, emailAddress, client.IPAddress); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByEmail(emailAddress)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return nil
+		}
+		return err
+	}
+	if user.IsGuest || user.IsServiceAccount {
+		return nil
+	}
+
+	ttl := time.Duration(s.accountCfg.ResetTTLMinutes) * time.Minute
+	token, err := s.issueUserToken(user.ID, models.UserTokenResetPassword, ttl)
+	if err != nil {
+		return err
+	}
+
+	msg, err := email.ResetPassword(user.Email, email.LinkData{
+		Name:      user.Name,
+		Link:      linkWithToken(s.accountCfg.ResetPasswordURL, token),
+		ExpiresIn: humanizeDuration(ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deliver(msg)
+	return nil
+}
+
+// ResetPassword redeems a reset token, sets the new password and signs out
+// every session. Receiving the link also proves the address is the user's.
+func (s *authService) ResetPassword(token, newPassword string) error {
+	if !auth.IsPasswordValid(newPassword) {
+		return ErrWeakPassword
+	}
+
+	userToken, err := s.tokenRepo.Consume(models.UserTokenResetPassword, auth.HashOpaqueToken(token))
+	if err != nil {
+		if err == repository.ErrUserTokenInvalid {
+			return ErrInvalidUserToken
+		}
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(userToken.UserID)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := auth.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	user.Password = hashedPassword
+	if !user.IsEmailVerified() {
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	return s.revocations.RevokeBefore(context.Background(), user.ID, now)
+}
+
+// sendVerificationEmail issues a verification token and emails its link
+func (s *authService) sendVerificationEmail(user *models.User) error {
+	ttl := time.Duration(s.accountCfg.VerificationTTLHours) * time.Hour
+	token, err := s.issueUserToken(user.ID, models.UserTokenVerifyEmail, ttl)
+	if err != nil {
+		return err
+	}
+
+	msg, err := email.VerifyEmail(user.Email, email.LinkData{
+		Name:      user.Name,
+		Link:      linkWithToken(s.accountCfg.VerifyEmailURL, token),
+		ExpiresIn: humanizeDuration(ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deliver(msg)
+	return nil
+}
+
+// issueUserToken replaces the user's outstanding tokens for the purpose with
+// a new one and returns it; only its hash is stored
+func (s *authService) issueUserToken(userID uuid.UUID, purpose models.UserTokenPurpose, ttl time.Duration) (string, error) {
+	if err := s.tokenRepo.InvalidateForUser(userID, purpose); err != nil {
+		return "", err
+	}
+
+	token, hash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.tokenRepo.Create(&models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// deliver sends the message in the background so response times do not
+// depend on the mail server
+func (s *authService) deliver(msg mail.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+		defer cancel()
+
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			log.Printf("Auth: failed to send %q email: %v", msg.Subject, err)
+		}
+	}()
+}
+
+func linkWithToken(base, token string) string {
+	separator := "?"
+	if u, err := url.Parse(base); err == nil && u.RawQuery != "" {
+		separator = "&"
+	}
+	return base + separator + "token=" + url.QueryEscape(token)
+}
+
+// truncate shortens s to at most maxLen bytes without splitting a character
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return strings.ToValidUTF8(s[:maxLen], "")
+}
+
+// humanizeDuration formats whole hours or minutes for email copy
+func humanizeDuration(d time.Duration) string {
+	unit, n := "minute", int(d.Minutes())
+	if d >= time.Hour && d%time.Hour == 0 {
+		unit, n = "hour", int(d.Hours())
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", n, unit)
+}
@@ -0,0 +1,96 @@
+package service
+
+// Runes that combine with an emoji into a single symbol
+const (
+	zeroWidthJoiner   = 0x200D
+	variationSelector = 0xFE0F
+	combiningKeycap   = 0x20E3
+	tagCancel         = 0xE007F
+)
+
+// isSingleEmoji reports whether s is exactly one emoji: a pictograph with
+// optional variation selector, skin tone and tag sequence, several of them
+// joined with zero-width joiners, a flag or a keycap
+func isSingleEmoji(s string) bool {
+	runes := []rune(s)
+	i, ok := scanEmoji(runes, 0)
+	if !ok {
+		return false
+	}
+	for i < len(runes) && runes[i] == zeroWidthJoiner {
+		if i, ok = scanEmoji(runes, i+1); !ok {
+			return false
+		}
+	}
+	return i == len(runes)
+}
+
+// scanEmoji consumes one emoji element starting at i and returns the index
+// after it
+func scanEmoji(runes []rune, i int) (int, bool) {
+	if i >= len(runes) {
+		return i, false
+	}
+	r := runes[i]
+
+	switch {
+	case isRegionalIndicator(r):
+		// Flags are a pair of regional indicators
+		if i+1 < len(runes) && isRegionalIndicator(runes[i+1]) {
+			return i + 2, true
+		}
+		return i, false
+
+	case r == '#' || r == '*' || (r >= '0' && r <= '9'):
+		i++
+		if i < len(runes) && runes[i] == variationSelector {
+			i++
+		}
+		if i < len(runes) && runes[i] == combiningKeycap {
+			return i + 1, true
+		}
+		return i, false
+
+	case isPictographic(r):
+		i++
+		for i < len(runes) && (runes[i] == variationSelector || isSkinTone(runes[i])) {
+			i++
+		}
+		// Subdivision flags end in a run of tag characters
+		if i < len(runes) && isTag(runes[i]) {
+			for i < len(runes) && isTag(runes[i]) {
+				i++
+			}
+			if runes[i-1] != tagCancel {
+				return i, false
+			}
+		}
+		return i, true
+	}
+	return i, false
+}
+
+func isRegionalIndicator(r rune) bool { return r >= 0x1F1E6 && r <= 0x1F1FF }
+
+func isSkinTone(r rune) bool { return r >= 0x1F3FB && r <= 0x1F3FF }
+
+func isTag(r rune) bool { return r >= 0xE0020 && r <= tagCancel }
+
+// isPictographic approximates Unicode's Extended_Pictographic property
+func isPictographic(r rune) bool {
+	switch {
+	case r == 0x00A9, r == 0x00AE, r == 0x203C, r == 0x2049, r == 0x2122,
+		r == 0x2139, r == 0x2328, r == 0x23CF, r == 0x24C2, r == 0x25B6,
+		r == 0x25C0, r == 0x2B50, r == 0x2B55, r == 0x3030, r == 0x303D,
+		r == 0x3297, r == 0x3299:
+		return true
+	case r >= 0x2194 && r <= 0x2199, r >= 0x21A9 && r <= 0x21AA,
+		r >= 0x231A && r <= 0x231B, r >= 0x23E9 && r <= 0x23F3,
+		r >= 0x23F8 && r <= 0x23FA, r >= 0x25AA && r <= 0x25AB,
+		r >= 0x25FB && r <= 0x25FE, r >= 0x2600 && r <= 0x27BF,
+		r >= 0x2934 && r <= 0x2935, r >= 0x2B05 && r <= 0x2B07,
+		r >= 0x2B1B && r <= 0x2B1C, r >= 0x1F000 && r <= 0x1FAFF:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/config"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/pkg/auth"
+	"github.com/meet-app/backend/pkg/ratelimit"
+)
+
+var ErrInvalidDisplayName = errors.New("display name must be between 1 and 50 characters")
+
+const (
+	maxDisplayNameLength = 50
+
+	// Time allowed for the rate limit check of a single guest join
+	guestJoinCheckTimeout = 5 * time.Second
+
+	// How often guests who were never admitted are removed
+	guestCleanupInterval = time.Hour
+
+	// Hex digits of the user ID in a guest's username, which keeps it well
+	// within maxUsernameLength
+	guestUsernameIDLength = 12
+)
+
+// GuestJoinThrottledError is returned when an IP address asked for too many
+// guest tokens
+type GuestJoinThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *GuestJoinThrottledError) Error() string {
+	return fmt.Sprintf("too many guest joins, retry after %s", e.RetryAfter)
+}
+
+// GuestSession is what an anonymous guest gets to connect to one meeting
+type GuestSession struct {
+	User      *models.User
+	Meeting   *models.Meeting
+	Token     string
+	ExpiresAt time.Time
+}
+
+type GuestService interface {
+	JoinAsGuest(code, displayName, passcode, clientIP string) (*GuestSession, error)
+	RunCleanup(ctx context.Context)
+}
+
+type guestService struct {
+	userRepo       repository.UserRepository
+	meetingService MeetingService
+	limiter        *ratelimit.TokenBucket
+	keys           *auth.Keyring
+	jwtCfg         *config.JWTConfig
+}
+
+func NewGuestService(
+	userRepo repository.UserRepository,
+	meetingService MeetingService,
+	limiter *ratelimit.TokenBucket,
+	keys *auth.Keyring,
+	jwtCfg *config.JWTConfig,
+) GuestService {
+	return &guestService{
+		userRepo:       userRepo,
+		meetingService: meetingService,
+		limiter:        limiter,
+		keys:           keys,
+		jwtCfg:         jwtCfg,
+	}
+}
+
+// JoinAsGuest creates an anonymous guest for the meeting and issues a token
+// that only works for the meeting's signaling and events. Each IP address
+// gets a limited number of guests.
+func (s *guestService) JoinAsGuest(code, displayName, passcode, clientIP string) (*GuestSession, error) {
+	displayName = strings.TrimSpace(displayName)
+	if displayName == "" || len([]rune(displayName)) > maxDisplayNameLength {
+		return nil, ErrInvalidDisplayName
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), guestJoinCheckTimeout)
+	defer cancel()
+
+	allowed, retryAfter, err := s.limiter.Allow(ctx, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, &GuestJoinThrottledError{RetryAfter: retryAfter}
+	}
+
+	meeting, err := s.meetingService.GetMeetingByCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.meetingService.VerifyGuestAccess(meeting.ID, passcode, clientIP); err != nil {
+		return nil, err
+	}
+
+	user, err := s.createGuestUser(displayName)
+	if err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := auth.GenerateGuestToken(
+		user.ID,
+		user.Name,
+		meeting.ID,
+		s.keys,
+		time.Duration(s.jwtCfg.GuestExpiryMinutes)*time.Minute,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GuestSession{
+		User:      user,
+		Meeting:   meeting,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// createGuestUser gives the guest a user row so participants and messages
+// can refer to them. Usernames are short prefixes of the ID, retried on the
+// rare collision.
+func (s *guestService) createGuestUser(displayName string) (*models.User, error) {
+	for attempt := 0; attempt < usernameAttempts; attempt++ {
+		id := uuid.New()
+		user := &models.User{
+			ID:       id,
+			Email:    id.String() + "@guest.invalid",
+			Username: "g-" + strings.ReplaceAll(id.String(), "-", "")[:guestUsernameIDLength],
+			Name:     displayName,
+			IsGuest:  true,
+		}
+		err := s.userRepo.Create(user)
+		if err == repository.ErrUsernameAlreadyExists {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+	return nil, repository.ErrUsernameAlreadyExists
+}
+
+// RunCleanup removes guests who were never admitted to their meeting once
+// their token has expired, until the context is done. Every server may run
+// it; removing the same guests twice is harmless.
+func (s *guestService) RunCleanup(ctx context.Context) {
+	ticker := time.NewTicker(guestCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		s.cleanup()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *guestService) cleanup() {
+	cutoff := time.Now().Add(-time.Duration(s.jwtCfg.GuestExpiryMinutes) * time.Minute)
+	removed, err := s.userRepo.DeleteUnadmittedGuests(cutoff)
+	if err != nil {
+		log.Printf("Guest: failed to remove expired guests: %v", err)
+		return
+	}
+	if removed > 0 {
+		log.Printf("Guest: removed %d guests who were never admitted", removed)
+	}
+}
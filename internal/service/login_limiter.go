@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/meet-app/backend/internal/config"
+	"github.com/meet-app/backend/pkg/ratelimit"
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginThrottledError is returned when an account or IP address must wait
+// before trying to log in again
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed logins, retry after %s", e.RetryAfter)
+}
+
+// LoginLimiter slows down password guessing. Counters are keyed by the
+// submitted email whether or not an account uses it, so throttling does not
+// reveal which addresses are registered.
+type LoginLimiter struct {
+	accountBackoff *ratelimit.Backoff
+	ipBackoff      *ratelimit.Backoff
+	accountLockout *ratelimit.Lockout
+}
+
+func NewLoginLimiter(client *redis.Client, cfg *config.LoginConfig) *LoginLimiter {
+	base := time.Duration(cfg.BackoffBaseSeconds) * time.Second
+	maxDelay := time.Duration(cfg.BackoffMaxSeconds) * time.Second
+	window := time.Duration(cfg.FailureWindowMinutes) * time.Minute
+
+	return &LoginLimiter{
+		accountBackoff: ratelimit.NewBackoff(client, "backoff:login:account:", cfg.AccountFreeAttempts, base, maxDelay, window),
+		ipBackoff:      ratelimit.NewBackoff(client, "backoff:login:ip:", cfg.IPFreeAttempts, base, maxDelay, window),
+		accountLockout: ratelimit.NewLockout(
+			client,
+			"lockout:login:",
+			cfg.LockoutThreshold,
+			window,
+			time.Duration(cfg.LockoutMinutes)*time.Minute,
+		),
+	}
+}
+
+// Wait returns how long the longest of the account's and IP address's
+// blocks still lasts, or 0 if a login may be attempted
+func (l *LoginLimiter) Wait(ctx context.Context, email, clientIP string) (time.Duration, error) {
+	account := loginAccountKey(email)
+
+	locked, err := l.accountLockout.Locked(ctx, account)
+	if err != nil {
+		return 0, err
+	}
+	accountWait, err := l.accountBackoff.Wait(ctx, account)
+	if err != nil {
+		return 0, err
+	}
+	ipWait, err := l.ipBackoff.Wait(ctx, clientIP)
+	if err != nil {
+		return 0, err
+	}
+
+	return maxDuration(locked, accountWait, ipWait), nil
+}
+
+// Fail records a failed login for the account and IP address
+func (l *LoginLimiter) Fail(ctx context.Context, email, clientIP string) error {
+	account := loginAccountKey(email)
+
+	if _, err := l.accountLockout.Fail(ctx, account); err != nil {
+		return err
+	}
+	if _, err := l.accountBackoff.Fail(ctx, account); err != nil {
+		return err
+	}
+	_, err := l.ipBackoff.Fail(ctx, clientIP)
+	return err
+}
+
+// Throttle records a request that must not be repeated quickly, such as a
+// password reset, for the account and IP address. It backs off like a failed
+// login but does not count towards the account lockout.
+func (l *LoginLimiter) Throttle(ctx context.Context, email, clientIP string) error {
+	if _, err := l.accountBackoff.Fail(ctx, loginAccountKey(email)); err != nil {
+		return err
+	}
+	_, err := l.ipBackoff.Fail(ctx, clientIP)
+	return err
+}
+
+// Succeed clears the account's failures. The IP address keeps its count so
+// one known password cannot be used to keep guessing others.
+func (l *LoginLimiter) Succeed(ctx context.Context, email string) error {
+	account := loginAccountKey(email)
+
+	if err := l.accountLockout.Reset(ctx, account); err != nil {
+		return err
+	}
+	return l.accountBackoff.Reset(ctx, account)
+}
+
+func loginAccountKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func maxDuration(durations ...time.Duration) time.Duration {
+	var longest time.Duration
+	for _, d := range durations {
+		if d > longest {
+			longest = d
+		}
+	}
+	return longest
+}
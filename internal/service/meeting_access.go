@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/pkg/auth"
+)
+
+var (
+	ErrPasscodeRequired      = errors.New("meeting requires a passcode")
+	ErrInvalidPasscode       = errors.New("invalid meeting passcode")
+	ErrInvalidPasscodeFormat = errors.New("passcode must be between 4 and 64 characters")
+	ErrInvalidInvite         = errors.New("invalid or expired invite link")
+	ErrInvalidInviteOptions  = errors.New("invalid invite options")
+	ErrGuestsNotAllowed      = errors.New("meeting does not allow guests")
+	ErrWaitingRoomRequired   = errors.New("ask to join through the waiting room")
+)
+
+// PasscodeLockedError is returned when too many wrong passcodes were entered
+// for a meeting
+type PasscodeLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *PasscodeLockedError) Error() string {
+	return fmt.Sprintf("too many wrong passcodes, retry after %s", e.RetryAfter)
+}
+
+const (
+	minPasscodeLength = 4
+	maxPasscodeLength = 64
+
+	// Longest lifetime a host can give an invite link
+	maxInviteLifetime = 30 * 24 * time.Hour
+
+	// Time allowed for the passcode lockout checks of a single join
+	accessCheckTimeout = 5 * time.Second
+)
+
+// JoinCredentials are what a first-time participant presents to get in
+type JoinCredentials struct {
+	Passcode    string
+	InviteToken string
+}
+
+// JoinGrant describes how a user was let into a meeting
+type JoinGrant struct {
+	// InviteID is set when access came from an invite link
+	InviteID          *uuid.UUID
+	BypassWaitingRoom bool
+	// Reason tells the user why they skipped the waiting room
+	Reason string
+}
+
+// InviteOptions configure a new invite link
+type InviteOptions struct {
+	ExpiresIn         time.Duration
+	MaxUses           int
+	BypassWaitingRoom bool
+}
+
+// SetMeetingPasscode sets or, with an empty passcode, removes the meeting
+// passcode (host only)
+func (s *meetingService) SetMeetingPasscode(meetingID, userID uuid.UUID, passcode string) error {
+	meeting, err := s.meetingRepo.FindByID(meetingID)
+	if err != nil {
+		return err
+	}
+	if meeting.HostID != userID {
+		return ErrUnauthorizedAccess
+	}
+
+	hash, err := hashPasscode(passcode)
+	if err != nil {
+		return err
+	}
+	return s.meetingRepo.UpdatePasscode(meetingID, hash)
+}
+
+// VerifyJoinAccess checks that a user may join the meeting, either because
+// they were in it before, belong to its organization, through an invite link
+// or with the passcode. It does not use up the invite; JoinMeeting does when
+// the participant is created.
+func (s *meetingService) VerifyJoinAccess(meetingID, userID uuid.UUID, creds JoinCredentials) (*JoinGrant, error) {
+	meeting, err := s.meetingRepo.FindByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	return s.verifyJoinAccess(meeting, userID, creds)
+}
+
+func (s *meetingService) verifyJoinAccess(meeting *models.Meeting, userID uuid.UUID, creds JoinCredentials) (*JoinGrant, error) {
+	if meeting.HostID == userID {
+		return &JoinGrant{BypassWaitingRoom: true, Reason: "Admitted as host"}, nil
+	}
+
+	// Organization-only meetings let the organization's members straight in
+	// and send everyone else to the waiting room, even if they were let in
+	// before or hold an invite that skips it
+	orgOnly := meeting.IsOrganizationOnly()
+	if orgOnly {
+		_, err := s.orgRepo.FindMember(*meeting.OrganizationID, userID)
+		if err == nil {
+			return &JoinGrant{BypassWaitingRoom: true, Reason: "Admitted as organization member"}, nil
+		}
+		if err != repository.ErrOrganizationMemberNotFound {
+			return nil, err
+		}
+	}
+
+	// Returning participants were let in already
+	_, err := s.participantRepo.FindByUserAndMeeting(userID, meeting.ID)
+	if err == nil {
+		if orgOnly {
+			return &JoinGrant{}, nil
+		}
+		return &JoinGrant{BypassWaitingRoom: true, Reason: "Auto-approved (returning user)"}, nil
+	}
+	if err != repository.ErrParticipantNotFound {
+		return nil, err
+	}
+
+	if creds.InviteToken != "" {
+		invite, err := s.findUsableInvite(meeting.ID, creds.InviteToken)
+		if err != nil {
+			return nil, err
+		}
+		return &JoinGrant{
+			InviteID:          &invite.ID,
+			BypassWaitingRoom: invite.BypassWaitingRoom && !orgOnly,
+			Reason:            "Admitted with invite link",
+		}, nil
+	}
+
+	if meeting.HasPasscode() {
+		if err := s.checkPasscode(meeting, userID.String(), creds.Passcode); err != nil {
+			return nil, err
+		}
+	}
+
+	return &JoinGrant{}, nil
+}
+
+// VerifyGuestAccess checks that an anonymous guest may ask to join the
+// meeting. Wrong passcodes are counted per client, e.g. its IP address.
+func (s *meetingService) VerifyGuestAccess(meetingID uuid.UUID, passcode, clientKey string) error {
+	meeting, err := s.meetingRepo.FindByID(meetingID)
+	if err != nil {
+		return err
+	}
+	if meeting.Status == models.MeetingStatusEnded {
+		return ErrMeetingEnded
+	}
+	if !meeting.Settings.AllowGuests {
+		return ErrGuestsNotAllowed
+	}
+
+	if meeting.HasPasscode() {
+		return s.checkPasscode(meeting, "guest:"+clientKey, passcode)
+	}
+	return nil
+}
+
+// checkPasscode verifies the passcode, locking the attempter out of the
+// meeting after too many wrong attempts
+func (s *meetingService) checkPasscode(meeting *models.Meeting, attempter, passcode string) error {
+	if passcode == "" {
+		return ErrPasscodeRequired
+	}
+	if s.passcodeLockout == nil {
+		if auth.VerifyPassword(meeting.PasscodeHash, passcode) != nil {
+			return ErrInvalidPasscode
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), accessCheckTimeout)
+	defer cancel()
+
+	key := meeting.ID.String() + ":" + attempter
+	retryAfter, err := s.passcodeLockout.Locked(ctx, key)
+	if err != nil {
+		return err
+	}
+	if retryAfter > 0 {
+		return &PasscodeLockedError{RetryAfter: retryAfter}
+	}
+
+	if auth.VerifyPassword(meeting.PasscodeHash, passcode) != nil {
+		retryAfter, err := s.passcodeLockout.Fail(ctx, key)
+		if err != nil {
+			return err
+		}
+		if retryAfter > 0 {
+			return &PasscodeLockedError{RetryAfter: retryAfter}
+		}
+		return ErrInvalidPasscode
+	}
+
+	return s.passcodeLockout.Reset(ctx, key)
+}
+
+// findUsableInvite resolves an invite token to an invite of the meeting that
+// can still admit someone
+func (s *meetingService) findUsableInvite(meetingID uuid.UUID, token string) (*models.MeetingInvite, error) {
+	claims, err := auth.ParseInvite(token, s.inviteSecret)
+	if err != nil || claims.MeetingID != meetingID {
+		return nil, ErrInvalidInvite
+	}
+
+	invite, err := s.inviteRepo.FindByID(claims.InviteID)
+	if err != nil {
+		if err == repository.ErrInviteNotFound {
+			return nil, ErrInvalidInvite
+		}
+		return nil, err
+	}
+	if invite.MeetingID != meetingID || !invite.IsUsable(time.Now()) {
+		return nil, ErrInvalidInvite
+	}
+
+	return invite, nil
+}
+
+// CreateInvite issues a signed invite link for the meeting (host only)
+func (s *meetingService) CreateInvite(meetingID, userID uuid.UUID, opts InviteOptions) (*models.MeetingInvite, error) {
+	meeting, err := s.meetingRepo.FindByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if meeting.HostID != userID {
+		return nil, ErrUnauthorizedAccess
+	}
+	if meeting.Status == models.MeetingStatusEnded {
+		return nil, ErrMeetingEnded
+	}
+
+	if opts.ExpiresIn == 0 {
+		opts.ExpiresIn = s.inviteLifetime
+	}
+	if opts.ExpiresIn < 0 || opts.ExpiresIn > maxInviteLifetime || opts.MaxUses < 0 {
+		return nil, ErrInvalidInviteOptions
+	}
+
+	invite := &models.MeetingInvite{
+		MeetingID:         meetingID,
+		CreatedBy:         userID,
+		ExpiresAt:         time.Now().Add(opts.ExpiresIn),
+		MaxUses:           opts.MaxUses,
+		BypassWaitingRoom: opts.BypassWaitingRoom,
+	}
+	if err := s.inviteRepo.Create(invite); err != nil {
+		return nil, err
+	}
+
+	return invite, nil
+}
+
+// ListInvites returns the invite links of the meeting (host only)
+func (s *meetingService) ListInvites(meetingID, userID uuid.UUID) ([]models.MeetingInvite, error) {
+	meeting, err := s.meetingRepo.FindByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if meeting.HostID != userID {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	return s.inviteRepo.FindByMeetingID(meetingID)
+}
+
+// RevokeInvite stops an invite link from admitting anyone (host only)
+func (s *meetingService) RevokeInvite(meetingID, userID, inviteID uuid.UUID) error {
+	meeting, err := s.meetingRepo.FindByID(meetingID)
+	if err != nil {
+		return err
+	}
+	if meeting.HostID != userID {
+		return ErrUnauthorizedAccess
+	}
+
+	return s.inviteRepo.Revoke(inviteID, meetingID)
+}
+
+// InviteLink returns the signed token and shareable URL of an invite
+func (s *meetingService) InviteLink(meeting *models.Meeting, invite *models.MeetingInvite) (string, string, error) {
+	token, err := auth.SignInvite(auth.InviteClaims{
+		InviteID:  invite.ID,
+		MeetingID: invite.MeetingID,
+		ExpiresAt: invite.ExpiresAt.Unix(),
+	}, s.inviteSecret)
+	if err != nil {
+		return "", "", err
+	}
+
+	link := strings.TrimRight(s.joinURL, "/") + "/" + url.PathEscape(meeting.Code) +
+		"?invite=" + url.QueryEscape(token)
+	return token, link, nil
+}
+
+// hashPasscode validates and hashes a meeting passcode; an empty passcode
+// hashes to the empty string, meaning none is required
+func hashPasscode(passcode string) (string, error) {
+	if passcode == "" {
+		return "", nil
+	}
+	if length := len([]rune(passcode)); length < minPasscodeLength || length > maxPasscodeLength {
+		return "", ErrInvalidPasscodeFormat
+	}
+	return auth.HashPassword(passcode)
+}
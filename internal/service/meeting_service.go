@@ -2,63 +2,205 @@ package service
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/attendance"
+	"github.com/meet-app/backend/internal/config"
+	"github.com/meet-app/backend/internal/meetingcode"
 	"github.com/meet-app/backend/internal/models"
 	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/pkg/ratelimit"
 )
 
 var (
 	ErrMeetingFull         = errors.New("meeting has reached maximum participants")
 	ErrUnauthorizedAccess  = errors.New("unauthorized to perform this action")
 	ErrAlreadyInMeeting    = errors.New("user is already in the meeting")
+	ErrNotInMeeting        = errors.New("user is not in the meeting")
+	ErrInvalidRole         = errors.New("role must be moderator or guest")
+	ErrInvalidListFilter   = errors.New("invalid meeting list filter")
+	ErrMeetingEnded        = errors.New("meeting has ended")
+	ErrCodeGeneration      = errors.New("could not generate a unique meeting code")
+	ErrEmailNotVerified    = errors.New("email address must be verified to host meetings")
+	ErrHostMFARequired     = errors.New("two-factor authentication must be enabled to host meetings")
+	ErrInvalidVisibility   = errors.New("visibility must be public, or organization for organization meetings")
+)
+
+// MeetingConnections tears down the real-time connections of a meeting
+type MeetingConnections interface {
+	CloseMeeting(meetingID uuid.UUID)
+}
+
+const (
+	// Default and maximum page size of a meeting listing
+	defaultMeetingListLimit = 20
+	maxMeetingListLimit     = 100
 )
 
 type MeetingService interface {
-	CreateMeeting(hostID uuid.UUID, title, description string, settings models.MeetingSettings) (*models.Meeting, error)
+	CreateMeeting(hostID uuid.UUID, title, description, passcode string, settings *models.MeetingSettings, organizationID *uuid.UUID, visibility models.MeetingVisibility, client ClientInfo) (*models.Meeting, error)
+	RegenerateMeetingCode(meetingID, userID uuid.UUID) (*models.Meeting, error)
 	GetMeetingByCode(code string) (*models.Meeting, error)
 	GetMeetingByID(id uuid.UUID) (*models.Meeting, error)
 	GetUserMeetings(userID uuid.UUID) ([]models.Meeting, error)
-	JoinMeeting(userID, meetingID uuid.UUID, role models.ParticipantRole) (*models.Participant, error)
+	ListUserMeetings(params repository.MeetingListParams) ([]models.MeetingListItem, *repository.MeetingCursor, error)
+	ListOrganizationMeetings(orgID uuid.UUID, params repository.MeetingListParams) ([]models.MeetingListItem, *repository.MeetingCursor, error)
+	JoinMeeting(userID, meetingID uuid.UUID, role models.ParticipantRole, creds JoinCredentials) (*models.Participant, error)
+	AdmitParticipant(meetingID, userID uuid.UUID) (*models.Participant, error)
+	VerifyJoinAccess(meetingID, userID uuid.UUID, creds JoinCredentials) (*JoinGrant, error)
+	VerifyGuestAccess(meetingID uuid.UUID, passcode, clientKey string) error
+	SetMeetingPasscode(meetingID, userID uuid.UUID, passcode string) error
+	CreateInvite(meetingID, userID uuid.UUID, opts InviteOptions) (*models.MeetingInvite, error)
+	ListInvites(meetingID, userID uuid.UUID) ([]models.MeetingInvite, error)
+	RevokeInvite(meetingID, userID, inviteID uuid.UUID) error
+	InviteLink(meeting *models.Meeting, invite *models.MeetingInvite) (token, link string, err error)
 	LeaveMeeting(userID, meetingID uuid.UUID) error
 	StartMeeting(meetingID, userID uuid.UUID) error
-	EndMeeting(meetingID, userID uuid.UUID) error
-	UpdateMeetingSettings(meetingID, userID uuid.UUID, settings models.MeetingSettings) error
+	EndMeeting(meetingID, userID uuid.UUID, client ClientInfo) error
+	UpdateMeetingSettings(meetingID, userID uuid.UUID, patch models.MeetingSettingsPatch, client ClientInfo) (*models.MeetingSettings, error)
 	GetMeetingParticipants(meetingID uuid.UUID) ([]models.Participant, error)
 	UpdateParticipantMediaStatus(participantID uuid.UUID, isMuted, isVideoOn, isSharing bool) error
+	UpdateParticipantRole(meetingID, hostID, targetUserID uuid.UUID, role models.ParticipantRole) (*models.Participant, error)
+	GetAttendanceReport(meetingID, userID uuid.UUID) (*attendance.Report, error)
+	GetActiveMeetings() ([]models.Meeting, error)
+	AutoEndMeeting(meetingID uuid.UUID, actorID *uuid.UUID, reason string, client ClientInfo) error
+	ExpireScheduledMeetings(before time.Time) (int64, error)
+	FindDanglingParticipants(joinedBefore time.Time) ([]models.Participant, error)
+	CloseParticipantsOfEndedMeetings() (int64, error)
 }
 
 type meetingService struct {
 	meetingRepo     repository.MeetingRepository
 	participantRepo repository.ParticipantRepository
+	inviteRepo      repository.InviteRepository
+	userRepo        repository.UserRepository
+	orgRepo         repository.OrganizationRepository
+	audit           AuditService
+	connections     MeetingConnections
+	codes           *meetingcode.Generator
+	codeAttempts    int
+	passcodeLockout *ratelimit.Lockout
+	inviteSecret    string
+	inviteLifetime  time.Duration
+	joinURL         string
+	requireVerified bool
+	requireMFA      bool
 }
 
 func NewMeetingService(
 	meetingRepo repository.MeetingRepository,
 	participantRepo repository.ParticipantRepository,
+	inviteRepo repository.InviteRepository,
+	userRepo repository.UserRepository,
+	orgRepo repository.OrganizationRepository,
+	audit AuditService,
+	connections MeetingConnections,
+	codes *meetingcode.Generator,
+	passcodeLockout *ratelimit.Lockout,
+	meetingCfg *config.MeetingConfig,
 ) MeetingService {
+	codeAttempts := meetingCfg.CodeMaxAttempts
+	if codeAttempts <= 0 {
+		codeAttempts = 1
+	}
+
 	return &meetingService{
 		meetingRepo:     meetingRepo,
 		participantRepo: participantRepo,
+		connections:     connections,
+		inviteRepo:      inviteRepo,
+		userRepo:        userRepo,
+		orgRepo:         orgRepo,
+		audit:           audit,
+		codes:           codes,
+		codeAttempts:    codeAttempts,
+		passcodeLockout: passcodeLockout,
+		inviteSecret:    meetingCfg.InviteSecret,
+		inviteLifetime:  time.Duration(meetingCfg.InviteDefaultHours) * time.Hour,
+		joinURL:         meetingCfg.JoinURL,
+		requireVerified: meetingCfg.RequireVerifiedHost,
+		requireMFA:      meetingCfg.RequireMFAHost,
 	}
 }
 
 func (s *meetingService) CreateMeeting(
 	hostID uuid.UUID,
-	title, description string,
-	settings models.MeetingSettings,
+	title, description, passcode string,
+	settings *models.MeetingSettings,
+	organizationID *uuid.UUID,
+	visibility models.MeetingVisibility,
+	client ClientInfo,
 ) (*models.Meeting, error) {
+	if visibility == "" {
+		visibility = models.MeetingVisibilityPublic
+	}
+	if !visibility.IsValid() || (visibility == models.MeetingVisibilityOrganization && organizationID == nil) {
+		return nil, ErrInvalidVisibility
+	}
+
+	// Organization meetings are hosted by members and start from the
+	// organization's default settings
+	if organizationID != nil {
+		member, err := s.orgRepo.FindMember(*organizationID, hostID)
+		if err != nil {
+			if err == repository.ErrOrganizationMemberNotFound {
+				return nil, repository.ErrOrganizationNotFound
+			}
+			return nil, err
+		}
+		if settings == nil {
+			settings = member.Organization.DefaultSettings
+		}
+	}
+	if settings == nil {
+		settings = &models.MeetingSettings{}
+	}
+
+	if s.requireVerified || s.requireMFA {
+		host, err := s.userRepo.FindByID(hostID)
+		if err != nil {
+			return nil, err
+		}
+		// Service accounts have no mailbox or login; their owner must meet
+		// the policy in their place
+		if host.IsServiceAccount && host.OwnerID != nil {
+			host, err = s.userRepo.FindByID(*host.OwnerID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if s.requireVerified && !host.IsEmailVerified() {
+			return nil, ErrEmailNotVerified
+		}
+		if s.requireMFA && !host.IsMFAEnabled() {
+			return nil, ErrHostMFARequired
+		}
+	}
+
+	passcodeHash, err := hashPasscode(passcode)
+	if err != nil {
+		return nil, err
+	}
+
 	meeting := &models.Meeting{
-		Title:       title,
-		Description: description,
-		HostID:      hostID,
-		Status:      models.MeetingStatusScheduled,
-		MaxUsers:    50,
-		Settings:    settings,
+		Title:          title,
+		Description:    description,
+		HostID:         hostID,
+		Status:         models.MeetingStatusScheduled,
+		MaxUsers:       50,
+		Settings:       *settings,
+		PasscodeHash:   passcodeHash,
+		OrganizationID: organizationID,
+		Visibility:     visibility,
 	}
 
-	if err := s.meetingRepo.Create(meeting); err != nil {
+	err = s.withUniqueCode(func(code string) error {
+		meeting.Code = code
+		return s.meetingRepo.Create(meeting)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -74,10 +216,63 @@ func (s *meetingService) CreateMeeting(
 		return nil, err
 	}
 
+	s.audit.Record(&models.AuditEvent{
+		Action:    models.AuditActionMeetingCreated,
+		ActorID:   &hostID,
+		MeetingID: &meeting.ID,
+		Payload: map[string]interface{}{
+			"title":           meeting.Title,
+			"visibility":      meeting.Visibility,
+			"organization_id": meeting.OrganizationID,
+			"has_passcode":    meeting.HasPasscode(),
+		},
+	}, client)
+
 	// Reload meeting with host info
 	return s.meetingRepo.FindByID(meeting.ID)
 }
 
+// RegenerateMeetingCode gives the meeting a new code so links with the old
+// one stop working (host only)
+func (s *meetingService) RegenerateMeetingCode(meetingID, userID uuid.UUID) (*models.Meeting, error) {
+	meeting, err := s.meetingRepo.FindByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if meeting.HostID != userID {
+		return nil, ErrUnauthorizedAccess
+	}
+	if meeting.Status == models.MeetingStatusEnded {
+		return nil, ErrMeetingEnded
+	}
+
+	err = s.withUniqueCode(func(code string) error {
+		return s.meetingRepo.UpdateCode(meetingID, code)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.meetingRepo.FindByID(meetingID)
+}
+
+// withUniqueCode calls save with freshly generated codes until one is not
+// taken, up to the configured number of attempts. The unique code index
+// decides which codes are taken, even for concurrent requests.
+func (s *meetingService) withUniqueCode(save func(code string) error) error {
+	for attempt := 0; attempt < s.codeAttempts; attempt++ {
+		code, err := s.codes.Generate()
+		if err != nil {
+			return err
+		}
+
+		if err := save(code); err != repository.ErrMeetingCodeExists {
+			return err
+		}
+	}
+	return ErrCodeGeneration
+}
+
 func (s *meetingService) GetMeetingByCode(code string) (*models.Meeting, error) {
 	return s.meetingRepo.FindByCode(code)
 }
@@ -90,46 +285,186 @@ func (s *meetingService) GetUserMeetings(userID uuid.UUID) ([]models.Meeting, er
 	return s.meetingRepo.FindByHostID(userID)
 }
 
+// ListUserMeetings returns one page of the meetings a user hosted or attended
+func (s *meetingService) ListUserMeetings(
+	params repository.MeetingListParams,
+) ([]models.MeetingListItem, *repository.MeetingCursor, error) {
+	params.OrganizationID = nil
+	return s.listMeetings(params)
+}
+
+// ListOrganizationMeetings returns one page of an organization's meetings to
+// one of its members
+func (s *meetingService) ListOrganizationMeetings(
+	orgID uuid.UUID,
+	params repository.MeetingListParams,
+) ([]models.MeetingListItem, *repository.MeetingCursor, error) {
+	if _, err := s.orgRepo.FindMember(orgID, params.UserID); err != nil {
+		if err == repository.ErrOrganizationMemberNotFound {
+			return nil, nil, repository.ErrOrganizationNotFound
+		}
+		return nil, nil, err
+	}
+
+	params.OrganizationID = &orgID
+	return s.listMeetings(params)
+}
+
+// listMeetings validates the listing filters and fetches one page
+func (s *meetingService) listMeetings(
+	params repository.MeetingListParams,
+) ([]models.MeetingListItem, *repository.MeetingCursor, error) {
+	switch params.Role {
+	case repository.MeetingListRoleAny, repository.MeetingListRoleHosted, repository.MeetingListRoleAttended:
+	default:
+		return nil, nil, ErrInvalidListFilter
+	}
+
+	switch params.Status {
+	case "", models.MeetingStatusScheduled, models.MeetingStatusActive, models.MeetingStatusEnded:
+	default:
+		return nil, nil, ErrInvalidListFilter
+	}
+
+	params.Query = strings.TrimSpace(params.Query)
+	if params.Limit <= 0 {
+		params.Limit = defaultMeetingListLimit
+	}
+	if params.Limit > maxMeetingListLimit {
+		params.Limit = maxMeetingListLimit
+	}
+
+	return s.meetingRepo.FindUserMeetings(params)
+}
+
 func (s *meetingService) JoinMeeting(
 	userID, meetingID uuid.UUID,
 	role models.ParticipantRole,
+	creds JoinCredentials,
 ) (*models.Participant, error) {
 	// Check if meeting exists
 	meeting, err := s.meetingRepo.FindByID(meetingID)
 	if err != nil {
 		return nil, err
 	}
+	if meeting.Status == models.MeetingStatusEnded {
+		return nil, ErrMeetingEnded
+	}
 
-	// Check if user is already in meeting
-	exists, err := s.participantRepo.IsUserInMeeting(userID, meetingID)
-	if err != nil {
+	// A participant who left earlier is brought back on the same row
+	existing, err := s.participantRepo.FindByUserAndMeeting(userID, meetingID)
+	if err != nil && err != repository.ErrParticipantNotFound {
 		return nil, err
 	}
-	if exists {
+	if existing != nil && existing.LeftAt == nil {
 		return nil, ErrAlreadyInMeeting
 	}
 
-	// Check if meeting is full
-	count, err := s.participantRepo.CountActiveMeetingParticipants(meetingID)
+	// First-time participants need the passcode or an invite link, and
+	// organization-only meetings check everyone again. Those the meeting or
+	// their invite sends to the waiting room must ask the host over /ws.
+	var inviteID *uuid.UUID
+	if existing == nil || meeting.IsOrganizationOnly() {
+		grant, err := s.verifyJoinAccess(meeting, userID, creds)
+		if err != nil {
+			return nil, err
+		}
+		if !grant.BypassWaitingRoom && (meeting.IsOrganizationOnly() || grant.InviteID != nil) {
+			return nil, ErrWaitingRoomRequired
+		}
+		inviteID = grant.InviteID
+	}
+
+	return s.addParticipant(meeting, existing, userID, role, inviteID)
+}
+
+// AdmitParticipant brings a user the host let in, or the host themselves,
+// into the meeting without asking for credentials: it creates their
+// participant or rejoins one who left, e.g. after a dropped connection.
+// Users already in the meeting are returned as they are.
+func (s *meetingService) AdmitParticipant(meetingID, userID uuid.UUID) (*models.Participant, error) {
+	meeting, err := s.meetingRepo.FindByID(meetingID)
 	if err != nil {
 		return nil, err
 	}
-	if count >= int64(meeting.MaxUsers) {
-		return nil, ErrMeetingFull
+	if meeting.Status == models.MeetingStatusEnded {
+		return nil, ErrMeetingEnded
 	}
 
-	// Create participant
-	participant := &models.Participant{
-		MeetingID: meetingID,
-		UserID:    userID,
-		Role:      role,
-		JoinedAt:  time.Now(),
-		IsMuted:   meeting.Settings.MuteOnJoin,
-		IsVideoOn: meeting.Settings.VideoOnJoin,
+	existing, err := s.participantRepo.FindByUserAndMeeting(userID, meetingID)
+	if err != nil && err != repository.ErrParticipantNotFound {
+		return nil, err
+	}
+	if existing != nil && existing.LeftAt == nil {
+		return existing, nil
 	}
 
-	if err := s.participantRepo.Create(participant); err != nil {
-		return nil, err
+	role := models.ParticipantRoleGuest
+	if meeting.HostID == userID {
+		role = models.ParticipantRoleHost
+	}
+	return s.addParticipant(meeting, existing, userID, role, nil)
+}
+
+// addParticipant creates the user's participant, or rejoins the existing one
+// who left, if the meeting has room. The host always has room. An invite
+// that admitted a new participant is used up once.
+func (s *meetingService) addParticipant(
+	meeting *models.Meeting,
+	existing *models.Participant,
+	userID uuid.UUID,
+	role models.ParticipantRole,
+	inviteID *uuid.UUID,
+) (*models.Participant, error) {
+	meetingID := meeting.ID
+
+	// Check if meeting is full
+	if meeting.HostID != userID {
+		count, err := s.participantRepo.CountActiveMeetingParticipants(meetingID)
+		if err != nil {
+			return nil, err
+		}
+		if count >= int64(meeting.MaxUsers) {
+			return nil, ErrMeetingFull
+		}
+	}
+
+	participant := existing
+	if participant != nil {
+		participant.IsMuted = meeting.Settings.MuteOnJoin
+		participant.IsVideoOn = meeting.Settings.VideoOnJoin
+		if err := s.participantRepo.Rejoin(participant); err != nil {
+			if err == repository.ErrParticipantAlreadyExists {
+				return nil, ErrAlreadyInMeeting
+			}
+			return nil, err
+		}
+	} else {
+		// Each participant admitted through an invite uses it up once
+		if inviteID != nil {
+			if err := s.inviteRepo.Consume(*inviteID); err != nil {
+				if err == repository.ErrInviteUnavailable {
+					return nil, ErrInvalidInvite
+				}
+				return nil, err
+			}
+		}
+
+		participant = &models.Participant{
+			MeetingID: meetingID,
+			UserID:    userID,
+			Role:      role,
+			JoinedAt:  time.Now(),
+			IsMuted:   meeting.Settings.MuteOnJoin,
+			IsVideoOn: meeting.Settings.VideoOnJoin,
+		}
+
+		if err := s.participantRepo.Create(participant); err != nil {
+			if err == repository.ErrParticipantAlreadyExists {
+				return nil, ErrAlreadyInMeeting
+			}
+			return nil, err
+		}
 	}
 
 	// If meeting is not active, activate it
@@ -148,8 +483,11 @@ func (s *meetingService) LeaveMeeting(userID, meetingID uuid.UUID) error {
 	if err != nil {
 		return err
 	}
+	if participant.LeftAt != nil {
+		return ErrNotInMeeting
+	}
 
-	// Mark as left
+	// Mark as left and close the open session
 	return s.participantRepo.MarkAsLeft(participant.ID)
 }
 
@@ -167,36 +505,89 @@ func (s *meetingService) StartMeeting(meetingID, userID uuid.UUID) error {
 	return s.meetingRepo.StartMeeting(meetingID)
 }
 
-func (s *meetingService) EndMeeting(meetingID, userID uuid.UUID) error {
-	// Verify user is host
+func (s *meetingService) EndMeeting(meetingID, userID uuid.UUID, client ClientInfo) error {
 	meeting, err := s.meetingRepo.FindByID(meetingID)
 	if err != nil {
 		return err
 	}
 
+	// The host can end the meeting, and so can the organization's owners and
+	// admins for organization meetings
+	reason := "host"
 	if meeting.HostID != userID {
-		return ErrUnauthorizedAccess
+		if meeting.OrganizationID == nil {
+			return ErrUnauthorizedAccess
+		}
+		member, err := s.orgRepo.FindMember(*meeting.OrganizationID, userID)
+		if err != nil {
+			if err == repository.ErrOrganizationMemberNotFound {
+				return ErrUnauthorizedAccess
+			}
+			return err
+		}
+		if !member.Role.CanManage() {
+			return ErrUnauthorizedAccess
+		}
+		reason = "organization_" + string(member.Role)
 	}
 
-	return s.meetingRepo.EndMeeting(meetingID)
+	return s.endMeeting(meetingID, &userID, reason, client)
 }
 
+// endMeeting ends the meeting and all participation in it, then disconnects
+// everyone still connected. Ending an ended meeting returns ErrMeetingEnded.
+func (s *meetingService) endMeeting(meetingID uuid.UUID, actorID *uuid.UUID, reason string, client ClientInfo) error {
+	if err := s.meetingRepo.EndMeeting(meetingID); err != nil {
+		if err == repository.ErrMeetingAlreadyEnded {
+			return ErrMeetingEnded
+		}
+		return err
+	}
+
+	if s.connections != nil {
+		s.connections.CloseMeeting(meetingID)
+	}
+
+	s.audit.Record(&models.AuditEvent{
+		Action:    models.AuditActionMeetingEnded,
+		ActorID:   actorID,
+		MeetingID: &meetingID,
+		Payload:   map[string]interface{}{"reason": reason},
+	}, client)
+	return nil
+}
+
+// UpdateMeetingSettings changes the settings present in the patch and
+// returns the result (host only)
 func (s *meetingService) UpdateMeetingSettings(
 	meetingID, userID uuid.UUID,
-	settings models.MeetingSettings,
-) error {
+	patch models.MeetingSettingsPatch,
+	client ClientInfo,
+) (*models.MeetingSettings, error) {
 	// Verify user is host
 	meeting, err := s.meetingRepo.FindByID(meetingID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if meeting.HostID != userID {
-		return ErrUnauthorizedAccess
+		return nil, ErrUnauthorizedAccess
 	}
 
-	meeting.Settings = settings
-	return s.meetingRepo.Update(meeting)
+	previous := meeting.Settings
+	patch.Apply(&meeting.Settings)
+	settings := meeting.Settings
+	if err := s.meetingRepo.Update(meeting); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(&models.AuditEvent{
+		Action:    models.AuditActionMeetingSettingsUpdated,
+		ActorID:   &userID,
+		MeetingID: &meetingID,
+		Payload:   map[string]interface{}{"previous": previous, "settings": settings},
+	}, client)
+	return &settings, nil
 }
 
 func (s *meetingService) GetMeetingParticipants(meetingID uuid.UUID) ([]models.Participant, error) {
@@ -209,3 +600,91 @@ func (s *meetingService) UpdateParticipantMediaStatus(
 ) error {
 	return s.participantRepo.UpdateMediaStatus(participantID, isMuted, isVideoOn, isSharing)
 }
+
+func (s *meetingService) UpdateParticipantRole(
+	meetingID, hostID, targetUserID uuid.UUID,
+	role models.ParticipantRole,
+) (*models.Participant, error) {
+	if role != models.ParticipantRoleModerator && role != models.ParticipantRoleGuest {
+		return nil, ErrInvalidRole
+	}
+
+	// Verify user is host
+	meeting, err := s.meetingRepo.FindByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if meeting.HostID != hostID || targetUserID == hostID {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	participant, err := s.participantRepo.FindByUserAndMeeting(targetUserID, meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.participantRepo.UpdateRole(participant.ID, role); err != nil {
+		return nil, err
+	}
+
+	participant.Role = role
+	return participant, nil
+}
+
+// GetAttendanceReport builds the attendance report of a meeting for its host
+// or moderators
+func (s *meetingService) GetAttendanceReport(meetingID, userID uuid.UUID) (*attendance.Report, error) {
+	meeting, err := s.meetingRepo.FindByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if meeting.HostID != userID {
+		participant, err := s.participantRepo.FindByUserAndMeeting(userID, meetingID)
+		if err != nil {
+			if err == repository.ErrParticipantNotFound {
+				return nil, ErrUnauthorizedAccess
+			}
+			return nil, err
+		}
+		if !participant.CanModerate() {
+			return nil, ErrUnauthorizedAccess
+		}
+	}
+
+	participants, err := s.participantRepo.FindByMeetingID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	return attendance.BuildReport(meeting, participants, time.Now()), nil
+}
+
+func (s *meetingService) GetActiveMeetings() ([]models.Meeting, error) {
+	return s.meetingRepo.FindActiveMeetings()
+}
+
+// AutoEndMeeting ends a meeting without checking who asked, e.g. when it
+// was left idle, ran past its maximum duration or an admin ended it. actorID
+// is nil when the server ends it on its own.
+func (s *meetingService) AutoEndMeeting(meetingID uuid.UUID, actorID *uuid.UUID, reason string, client ClientInfo) error {
+	return s.endMeeting(meetingID, actorID, reason, client)
+}
+
+// ExpireScheduledMeetings ends scheduled meetings that never started
+func (s *meetingService) ExpireScheduledMeetings(before time.Time) (int64, error) {
+	return s.meetingRepo.ExpireScheduledMeetings(before)
+}
+
+// FindDanglingParticipants returns participants of active meetings still
+// marked present since before joinedBefore; callers decide which of them
+// are actually gone
+func (s *meetingService) FindDanglingParticipants(joinedBefore time.Time) ([]models.Participant, error) {
+	return s.participantRepo.FindStaleActiveParticipants(joinedBefore)
+}
+
+// CloseParticipantsOfEndedMeetings marks anyone left in an ended meeting as gone
+func (s *meetingService) CloseParticipantsOfEndedMeetings() (int64, error) {
+	return s.participantRepo.CloseParticipantsOfEndedMeetings()
+}
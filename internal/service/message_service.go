@@ -1,33 +1,108 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/config"
 	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/moderation"
 	"github.com/meet-app/backend/internal/repository"
 	"github.com/meet-app/backend/internal/sse"
+	"github.com/meet-app/backend/pkg/ratelimit"
 )
 
+var (
+	ErrInvalidParentMessage = errors.New("parent message does not belong to this meeting")
+	ErrInvalidReaction      = errors.New("reaction must be a single emoji")
+	ErrPrivateChatDisabled  = errors.New("private chat is disabled in this meeting")
+	ErrInvalidRecipient     = errors.New("recipient is not a participant in this meeting")
+	ErrEmptySearchQuery     = errors.New("search query is required")
+	ErrMessageTooLong       = errors.New("message exceeds the maximum length")
+	ErrMessageRejected      = errors.New("message was rejected by moderation")
+)
+
+// RateLimitError is returned when a user sends messages faster than allowed
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("too many messages, retry after %s", e.RetryAfter)
+}
+
+const (
+	// maxReactionLength bounds the size of a reaction so it fits the emoji column
+	maxReactionLength = 32
+
+	// Default and maximum number of results returned by a message search
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	// Time allowed for rate limiting and moderation of a single message
+	sendCheckTimeout = 5 * time.Second
+
+	// Number of messages loaded per query when exporting a transcript
+	exportBatchSize = 500
+)
+
+// mentionPattern matches @username mentions inside message content
+var mentionPattern = regexp.MustCompile(`@([A-Za-z0-9_.\-]+)`)
+
 type MessageService interface {
-	SendMessage(userID, meetingID uuid.UUID, messageType models.MessageType, content string) (*models.Message, error)
-	GetMeetingMessages(meetingID uuid.UUID, limit int) ([]models.Message, error)
-	GetMeetingMessagesPaginated(meetingID uuid.UUID, offset, limit int) ([]models.Message, error)
-	DeleteMessage(messageID, userID uuid.UUID) error
+	SendMessage(userID, meetingID uuid.UUID, messageType models.MessageType, content string, parentID, recipientID *uuid.UUID) (*models.Message, error)
+	SendSystemMessage(meetingID, actorID uuid.UUID, key models.SystemMessageKey, params map[string]string) (*models.Message, error)
+	GetMeetingMessages(meetingID, viewerID uuid.UUID, limit int) ([]models.Message, error)
+	GetMeetingMessagesPaginated(meetingID, viewerID uuid.UUID, offset, limit int) ([]models.Message, error)
+	GetReplies(meetingID, messageID, viewerID uuid.UUID) ([]models.Message, error)
+	SearchMessages(params repository.MessageSearchParams) ([]models.MessageSearchResult, error)
+	GetFlaggedMessages(meetingID, userID uuid.UUID) ([]models.Message, error)
+	GetTranscriptMeeting(meetingID, userID uuid.UUID) (*models.Meeting, error)
+	StreamTranscript(meetingID, userID uuid.UUID, fn func([]models.Message) error) error
+	DeleteMessage(meetingID, messageID, userID uuid.UUID, client ClientInfo) error
+	AddReaction(userID, meetingID, messageID uuid.UUID, emoji string) (*models.Message, error)
+	RemoveReaction(userID, meetingID, messageID uuid.UUID, emoji string) (*models.Message, error)
 }
 
 type messageService struct {
 	messageRepo     repository.MessageRepository
+	meetingRepo     repository.MeetingRepository
 	participantRepo repository.ParticipantRepository
+	reactionRepo    repository.ReactionRepository
+	audit           AuditService
+	limiter         *ratelimit.TokenBucket
+	moderator       moderation.Moderator
+	chatCfg         *config.ChatConfig
 }
 
+// NewMessageService creates the chat service. limiter and moderator are
+// optional; when nil, messages are not rate limited or moderated.
 func NewMessageService(
 	messageRepo repository.MessageRepository,
+	meetingRepo repository.MeetingRepository,
 	participantRepo repository.ParticipantRepository,
+	reactionRepo repository.ReactionRepository,
+	audit AuditService,
+	limiter *ratelimit.TokenBucket,
+	moderator moderation.Moderator,
+	chatCfg *config.ChatConfig,
 ) MessageService {
 	return &messageService{
 		messageRepo:     messageRepo,
+		meetingRepo:     meetingRepo,
 		participantRepo: participantRepo,
+		reactionRepo:    reactionRepo,
+		audit:           audit,
+		limiter:         limiter,
+		moderator:       moderator,
+		chatCfg:         chatCfg,
 	}
 }
 
@@ -35,7 +110,12 @@ func (s *messageService) SendMessage(
 	userID, meetingID uuid.UUID,
 	messageType models.MessageType,
 	content string,
+	parentID, recipientID *uuid.UUID,
 ) (*models.Message, error) {
+	if s.chatCfg.MaxMessageLength > 0 && utf8.RuneCountInString(content) > s.chatCfg.MaxMessageLength {
+		return nil, ErrMessageTooLong
+	}
+
 	// Verify user is in meeting
 	isInMeeting, err := s.participantRepo.IsUserInMeeting(userID, meetingID)
 	if err != nil {
@@ -45,11 +125,75 @@ func (s *messageService) SendMessage(
 		return nil, ErrUnauthorizedAccess
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), sendCheckTimeout)
+	defer cancel()
+
+	if err := s.checkRateLimit(ctx, userID, meetingID); err != nil {
+		return nil, err
+	}
+
+	verdict, err := s.moderate(ctx, userID, meetingID, content)
+	if err != nil {
+		return nil, err
+	}
+	if verdict.Action == moderation.ActionReject {
+		log.Printf("[Chat] Message from user %s rejected in meeting %s: %s", userID, meetingID, verdict.Reason)
+		return nil, ErrMessageRejected
+	}
+	content = verdict.Content
+
+	// Replies always hang off the root message so threads stay one level deep
+	if parentID != nil {
+		parent, err := s.messageRepo.FindByID(*parentID)
+		if err != nil {
+			if err == repository.ErrMessageNotFound {
+				return nil, ErrInvalidParentMessage
+			}
+			return nil, err
+		}
+		if parent.MeetingID != meetingID || !parent.IsVisibleTo(userID) {
+			return nil, ErrInvalidParentMessage
+		}
+		if parent.ParentID != nil {
+			parentID = parent.ParentID
+		}
+
+		// Replies to a direct message stay private between the same two users
+		if parent.IsPrivate() {
+			other := parent.UserID
+			if other == userID {
+				other = *parent.RecipientID
+			}
+			recipientID = &other
+		}
+	}
+
+	if recipientID != nil {
+		if err := s.validateRecipient(userID, meetingID, *recipientID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Resolve @username mentions against the current participants;
+	// direct messages already notify their only recipient
+	var mentioned []uuid.UUID
+	if recipientID == nil {
+		mentioned, err = s.resolveMentions(userID, meetingID, content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	message := &models.Message{
-		MeetingID: meetingID,
-		UserID:    userID,
-		Type:      messageType,
-		Content:   content,
+		MeetingID:   meetingID,
+		UserID:      userID,
+		ParentID:    parentID,
+		RecipientID: recipientID,
+		Type:        messageType,
+		Content:     content,
+		Mentions:    mentioned,
+		IsFlagged:   verdict.Action == moderation.ActionFlag,
+		FlagReason:  verdict.Reason,
 	}
 
 	if err := s.messageRepo.Create(message); err != nil {
@@ -62,39 +206,414 @@ func (s *messageService) SendMessage(
 		return nil, err
 	}
 
-	// Broadcast message to all participants via SSE
+	if fullMessage.IsFlagged {
+		s.notifyModerators(meetingID, fullMessage)
+	}
+
 	hub := sse.GetHub()
-	hub.BroadcastToMeeting(meetingID, sse.Event{
+	response := fullMessage.ToResponse()
+	event := sse.Event{
+		Type: sse.EventChatMessage,
+		Data: response,
+	}
+
+	// Direct messages are only delivered to the sender and recipient streams
+	if fullMessage.IsPrivate() {
+		hub.SendToUser(meetingID, userID, event)
+		hub.SendToUser(meetingID, *recipientID, event)
+		log.Printf("[Chat] Private message sent in meeting %s from user %s to user %s", meetingID, userID, *recipientID)
+		return fullMessage, nil
+	}
+
+	// Broadcast message to all participants via SSE
+	hub.BroadcastToMeeting(meetingID, event)
+	log.Printf("[Chat] Message broadcast to meeting %s from user %s", meetingID, userID)
+
+	// Notify mentioned users on their own streams
+	for _, mentionedID := range mentioned {
+		hub.SendToUser(meetingID, mentionedID, sse.Event{
+			Type: sse.EventMention,
+			Data: response,
+		})
+	}
+
+	return fullMessage, nil
+}
+
+// SendSystemMessage appends an automatic system message to the chat log and
+// broadcasts it. If params has no "name", the actor's display name is used.
+func (s *messageService) SendSystemMessage(
+	meetingID, actorID uuid.UUID,
+	key models.SystemMessageKey,
+	params map[string]string,
+) (*models.Message, error) {
+	if params == nil {
+		params = make(map[string]string)
+	}
+	if _, ok := params["name"]; !ok {
+		if participant, err := s.participantRepo.FindByUserAndMeeting(actorID, meetingID); err == nil {
+			params["name"] = participant.User.Name
+		}
+	}
+
+	message := &models.Message{
+		MeetingID:      meetingID,
+		UserID:         actorID,
+		Type:           models.MessageTypeSystem,
+		Content:        key.Render(params),
+		TemplateKey:    key,
+		TemplateParams: params,
+	}
+
+	if err := s.messageRepo.Create(message); err != nil {
+		return nil, err
+	}
+
+	fullMessage, err := s.messageRepo.FindByID(message.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sse.GetHub().BroadcastToMeeting(meetingID, sse.Event{
 		Type: sse.EventChatMessage,
 		Data: fullMessage.ToResponse(),
 	})
-	log.Printf("[Chat] Message broadcast to meeting %s from user %s", meetingID, userID)
+	log.Printf("[Chat] System message %s broadcast to meeting %s", key, meetingID)
 
 	return fullMessage, nil
 }
 
-func (s *messageService) GetMeetingMessages(meetingID uuid.UUID, limit int) ([]models.Message, error) {
-	return s.messageRepo.FindByMeetingID(meetingID, limit)
+func (s *messageService) GetMeetingMessages(meetingID, viewerID uuid.UUID, limit int) ([]models.Message, error) {
+	return s.messageRepo.FindByMeetingID(meetingID, viewerID, limit)
 }
 
 func (s *messageService) GetMeetingMessagesPaginated(
-	meetingID uuid.UUID,
+	meetingID, viewerID uuid.UUID,
 	offset, limit int,
 ) ([]models.Message, error) {
-	return s.messageRepo.FindByMeetingIDPaginated(meetingID, offset, limit)
+	return s.messageRepo.FindByMeetingIDPaginated(meetingID, viewerID, offset, limit)
 }
 
-func (s *messageService) DeleteMessage(messageID, userID uuid.UUID) error {
-	// Find message
-	message, err := s.messageRepo.FindByID(messageID)
+func (s *messageService) GetReplies(meetingID, messageID, viewerID uuid.UUID) ([]models.Message, error) {
+	if _, err := s.findMeetingMessage(meetingID, messageID, viewerID); err != nil {
+		return nil, err
+	}
+
+	return s.messageRepo.FindReplies(messageID, viewerID)
+}
+
+func (s *messageService) SearchMessages(params repository.MessageSearchParams) ([]models.MessageSearchResult, error) {
+	params.Query = strings.TrimSpace(params.Query)
+	if params.Query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+
+	if params.Limit <= 0 {
+		params.Limit = defaultSearchLimit
+	}
+	if params.Limit > maxSearchLimit {
+		params.Limit = maxSearchLimit
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	return s.messageRepo.Search(params)
+}
+
+func (s *messageService) GetFlaggedMessages(meetingID, userID uuid.UUID) ([]models.Message, error) {
+	participant, err := s.participantRepo.FindByUserAndMeeting(userID, meetingID)
+	if err != nil {
+		if err == repository.ErrParticipantNotFound {
+			return nil, ErrUnauthorizedAccess
+		}
+		return nil, err
+	}
+	if !participant.CanModerate() {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	return s.messageRepo.FindFlaggedByMeetingID(meetingID)
+}
+
+// GetTranscriptMeeting returns the meeting if the user ever participated in it
+func (s *messageService) GetTranscriptMeeting(meetingID, userID uuid.UUID) (*models.Meeting, error) {
+	if _, err := s.participantRepo.FindByUserAndMeeting(userID, meetingID); err != nil {
+		if err == repository.ErrParticipantNotFound {
+			return nil, ErrUnauthorizedAccess
+		}
+		return nil, err
+	}
+
+	return s.meetingRepo.FindByID(meetingID)
+}
+
+// StreamTranscript calls fn with the meeting's messages, oldest first, in batches
+func (s *messageService) StreamTranscript(
+	meetingID, userID uuid.UUID,
+	fn func([]models.Message) error,
+) error {
+	return s.messageRepo.StreamByMeetingID(meetingID, userID, exportBatchSize, fn)
+}
+
+// DeleteMessage removes a message. Authors may delete their own messages;
+// the host and moderators may delete anyone's.
+func (s *messageService) DeleteMessage(meetingID, messageID, userID uuid.UUID, client ClientInfo) error {
+	message, err := s.findMeetingMessage(meetingID, messageID, userID)
 	if err != nil {
 		return err
 	}
 
-	// Verify user owns the message
 	if message.UserID != userID {
-		return ErrUnauthorizedAccess
+		participant, err := s.participantRepo.FindByUserAndMeeting(userID, meetingID)
+		if err != nil {
+			if err == repository.ErrParticipantNotFound {
+				return ErrUnauthorizedAccess
+			}
+			return err
+		}
+		if !participant.CanModerate() {
+			return ErrUnauthorizedAccess
+		}
+	}
+
+	if err := s.messageRepo.Delete(messageID); err != nil {
+		return err
+	}
+
+	event := sse.Event{
+		Type: sse.EventMessageDeleted,
+		Data: map[string]interface{}{"message_id": messageID, "meeting_id": meetingID},
+	}
+	hub := sse.GetHub()
+	if message.IsPrivate() {
+		hub.SendToUser(meetingID, message.UserID, event)
+		hub.SendToUser(meetingID, *message.RecipientID, event)
+	} else {
+		hub.BroadcastToMeeting(meetingID, event)
+	}
+
+	s.audit.Record(&models.AuditEvent{
+		Action:       models.AuditActionMessageDeleted,
+		ActorID:      &userID,
+		TargetUserID: &message.UserID,
+		MeetingID:    &message.MeetingID,
+		Payload:      map[string]interface{}{"message_id": message.ID},
+	}, client)
+	return nil
+}
+
+func (s *messageService) AddReaction(
+	userID, meetingID, messageID uuid.UUID,
+	emoji string,
+) (*models.Message, error) {
+	emoji, err := s.prepareReaction(userID, meetingID, messageID, emoji)
+	if err != nil {
+		return nil, err
+	}
+
+	reaction := &models.MessageReaction{
+		MessageID: messageID,
+		UserID:    userID,
+		Emoji:     emoji,
+	}
+
+	if err := s.reactionRepo.Create(reaction); err != nil {
+		return nil, err
+	}
+
+	return s.broadcastReaction(sse.EventReactionAdded, userID, meetingID, messageID, emoji)
+}
+
+func (s *messageService) RemoveReaction(
+	userID, meetingID, messageID uuid.UUID,
+	emoji string,
+) (*models.Message, error) {
+	emoji, err := s.prepareReaction(userID, meetingID, messageID, emoji)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.reactionRepo.Delete(messageID, userID, emoji); err != nil {
+		return nil, err
+	}
+
+	return s.broadcastReaction(sse.EventReactionRemoved, userID, meetingID, messageID, emoji)
+}
+
+// prepareReaction validates the emoji and verifies the user can react to the message
+func (s *messageService) prepareReaction(userID, meetingID, messageID uuid.UUID, emoji string) (string, error) {
+	emoji = strings.TrimSpace(emoji)
+	if len(emoji) > maxReactionLength || !isSingleEmoji(emoji) {
+		return "", ErrInvalidReaction
+	}
+
+	isInMeeting, err := s.participantRepo.IsUserInMeeting(userID, meetingID)
+	if err != nil {
+		return "", err
+	}
+	if !isInMeeting {
+		return "", ErrUnauthorizedAccess
+	}
+
+	if _, err := s.findMeetingMessage(meetingID, messageID, userID); err != nil {
+		return "", err
+	}
+
+	return emoji, nil
+}
+
+// broadcastReaction reloads the message and sends the reaction change to the meeting
+func (s *messageService) broadcastReaction(
+	eventType sse.EventType,
+	userID, meetingID, messageID uuid.UUID,
+	emoji string,
+) (*models.Message, error) {
+	message, err := s.messageRepo.FindByID(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := sse.Event{
+		Type: eventType,
+		Data: map[string]interface{}{
+			"message_id": messageID,
+			"user_id":    userID,
+			"emoji":      emoji,
+			"reactions":  models.AggregateReactions(message.Reactions),
+		},
+	}
+
+	hub := sse.GetHub()
+	if message.IsPrivate() {
+		hub.SendToUser(meetingID, message.UserID, event)
+		hub.SendToUser(meetingID, *message.RecipientID, event)
+		return message, nil
+	}
+
+	hub.BroadcastToMeeting(meetingID, event)
+	return message, nil
+}
+
+// checkRateLimit takes a token from the user's per-meeting bucket
+func (s *messageService) checkRateLimit(ctx context.Context, userID, meetingID uuid.UUID) error {
+	if s.limiter == nil {
+		return nil
+	}
+
+	allowed, retryAfter, err := s.limiter.Allow(ctx, meetingID.String()+":"+userID.String())
+	if err != nil {
+		// Don't take chat down with Redis; log and let the message through
+		log.Printf("[Chat] Rate limit check failed for user %s: %v", userID, err)
+		return nil
+	}
+	if !allowed {
+		return &RateLimitError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// moderate runs the configured moderator over the message content
+func (s *messageService) moderate(ctx context.Context, userID, meetingID uuid.UUID, content string) (moderation.Result, error) {
+	if s.moderator == nil {
+		return moderation.Allow(content), nil
+	}
+
+	return s.moderator.Moderate(ctx, moderation.Input{
+		MeetingID: meetingID,
+		UserID:    userID,
+		Content:   content,
+	})
+}
+
+// notifyModerators sends a flagged message to the host and moderators
+func (s *messageService) notifyModerators(meetingID uuid.UUID, message *models.Message) {
+	participants, err := s.participantRepo.FindActiveMeetingParticipants(meetingID)
+	if err != nil {
+		log.Printf("[Chat] Failed to load moderators for meeting %s: %v", meetingID, err)
+		return
+	}
+
+	hub := sse.GetHub()
+	event := sse.Event{
+		Type: sse.EventMessageFlagged,
+		Data: message.ToFlaggedResponse(),
+	}
+	for _, p := range participants {
+		if p.CanModerate() {
+			hub.SendToUser(meetingID, p.UserID, event)
+		}
+	}
+}
+
+// findMeetingMessage loads a message and ensures it belongs to the meeting
+// and is visible to the viewer
+func (s *messageService) findMeetingMessage(meetingID, messageID, viewerID uuid.UUID) (*models.Message, error) {
+	message, err := s.messageRepo.FindByID(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.MeetingID != meetingID || !message.IsVisibleTo(viewerID) {
+		return nil, repository.ErrMessageNotFound
+	}
+	return message, nil
+}
+
+// validateRecipient checks that private chat is enabled and the recipient
+// is another active participant of the meeting
+func (s *messageService) validateRecipient(senderID, meetingID, recipientID uuid.UUID) error {
+	if recipientID == senderID {
+		return ErrInvalidRecipient
+	}
+
+	meeting, err := s.meetingRepo.FindByID(meetingID)
+	if err != nil {
+		return err
+	}
+	if !meeting.Settings.AllowPrivateChat {
+		return ErrPrivateChatDisabled
+	}
+
+	isInMeeting, err := s.participantRepo.IsUserInMeeting(recipientID, meetingID)
+	if err != nil {
+		return err
+	}
+	if !isInMeeting {
+		return ErrInvalidRecipient
+	}
+
+	return nil
+}
+
+// resolveMentions returns the IDs of active participants mentioned by @username
+func (s *messageService) resolveMentions(senderID, meetingID uuid.UUID, content string) ([]uuid.UUID, error) {
+	matches := mentionPattern.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	participants, err := s.participantRepo.FindActiveMeetingParticipants(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	byUsername := make(map[string]uuid.UUID, len(participants))
+	for _, p := range participants {
+		byUsername[strings.ToLower(p.User.Username)] = p.UserID
+	}
+
+	var mentioned []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, match := range matches {
+		// Trailing punctuation such as "@alice." is not part of the username
+		username := strings.TrimRight(match[1], ".-")
+		id, ok := byUsername[strings.ToLower(username)]
+		if !ok || id == senderID || seen[id] {
+			continue
+		}
+		seen[id] = true
+		mentioned = append(mentioned, id)
 	}
 
-	return s.messageRepo.Delete(messageID)
+	return mentioned, nil
 }
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/config"
+	"github.com/meet-app/backend/pkg/auth"
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrInvalidMFAChallenge = errors.New("two-factor challenge is invalid or expired, log in again")
+
+const mfaChallengePrefix = "mfa:challenge:"
+
+// mfaChallengeFailScript counts a wrong code and discards the challenge once
+// ARGV[1] have been tried. Expired challenges are left alone so the counter
+// does not outlive them.
+var mfaChallengeFailScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+
+local attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+if attempts >= tonumber(ARGV[1]) then
+	redis.call('DEL', KEYS[1])
+end
+return attempts
+`)
+
+// MFARequiredError is returned by Login when the password was correct but
+// the account needs a second factor. The challenge token is exchanged for
+// session tokens together with a TOTP or recovery code.
+type MFARequiredError struct {
+	ChallengeToken string
+	ExpiresAt      time.Time
+}
+
+func (e *MFARequiredError) Error() string {
+	return "two-factor authentication required"
+}
+
+// MFAChallengeStore keeps the short-lived challenges between the password and
+// second-factor steps of a login. A challenge allows a few wrong codes
+// before it is discarded.
+type MFAChallengeStore struct {
+	client      *redis.Client
+	ttl         time.Duration
+	maxAttempts int
+}
+
+func NewMFAChallengeStore(client *redis.Client, cfg *config.MFAConfig) *MFAChallengeStore {
+	return &MFAChallengeStore{
+		client:      client,
+		ttl:         time.Duration(cfg.ChallengeTTLMinutes) * time.Minute,
+		maxAttempts: cfg.ChallengeMaxAttempts,
+	}
+}
+
+// Create starts a challenge for the user
+func (s *MFAChallengeStore) Create(ctx context.Context, userID uuid.UUID) (*MFARequiredError, error) {
+	token, hash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	key := mfaChallengePrefix + hash
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, "user_id", userID.String(), "attempts", 0)
+	pipe.Expire(ctx, key, s.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return &MFARequiredError{
+		ChallengeToken: token,
+		ExpiresAt:      time.Now().Add(s.ttl),
+	}, nil
+}
+
+// Lookup returns the user a challenge was created for
+func (s *MFAChallengeStore) Lookup(ctx context.Context, token string) (uuid.UUID, error) {
+	value, err := s.client.HGet(ctx, mfaChallengePrefix+auth.HashOpaqueToken(token), "user_id").Result()
+	if err != nil {
+		if err == redis.Nil {
+			return uuid.Nil, ErrInvalidMFAChallenge
+		}
+		return uuid.Nil, err
+	}
+
+	userID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, ErrInvalidMFAChallenge
+	}
+	return userID, nil
+}
+
+// Fail counts a wrong code, discarding the challenge after too many
+func (s *MFAChallengeStore) Fail(ctx context.Context, token string) error {
+	key := mfaChallengePrefix + auth.HashOpaqueToken(token)
+	return mfaChallengeFailScript.Run(ctx, s.client, []string{key}, s.maxAttempts).Err()
+}
+
+// Delete discards a challenge once it has been completed
+func (s *MFAChallengeStore) Delete(ctx context.Context, token string) error {
+	return s.client.Del(ctx, mfaChallengePrefix+auth.HashOpaqueToken(token)).Err()
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/config"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/pkg/auth"
+	"github.com/meet-app/backend/pkg/totp"
+)
+
+var (
+	ErrMFAAlreadyEnabled  = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled      = errors.New("two-factor authentication is not enabled")
+	ErrMFASetupNotStarted = errors.New("two-factor setup has not been started")
+	ErrInvalidMFACode     = errors.New("invalid two-factor code")
+)
+
+const (
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+
+	// Steps of clock drift accepted either way
+	totpSkew = 1
+)
+
+// recoveryCodeAlphabet leaves out characters that are easily confused
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// TOTPSetup is a new authenticator secret awaiting confirmation
+type TOTPSetup struct {
+	Secret          string
+	ProvisioningURI string // otpauth:// URI to show as a QR code
+}
+
+// MFAStatus describes a user's second factor
+type MFAStatus struct {
+	Enabled                bool
+	EnabledAt              *time.Time
+	RecoveryCodesRemaining int64
+}
+
+type MFAService interface {
+	Status(userID uuid.UUID) (*MFAStatus, error)
+	BeginTOTPSetup(userID uuid.UUID) (*TOTPSetup, error)
+	ConfirmTOTPSetup(userID uuid.UUID, code string) ([]string, error)
+	DisableTOTP(userID uuid.UUID, code string) error
+	RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error)
+	VerifyCode(user *models.User, code string) error
+}
+
+type mfaService struct {
+	userRepo     repository.UserRepository
+	recoveryRepo repository.RecoveryCodeRepository
+	issuer       string
+}
+
+func NewMFAService(
+	userRepo repository.UserRepository,
+	recoveryRepo repository.RecoveryCodeRepository,
+	mfaCfg *config.MFAConfig,
+) MFAService {
+	return &mfaService{
+		userRepo:     userRepo,
+		recoveryRepo: recoveryRepo,
+		issuer:       mfaCfg.Issuer,
+	}
+}
+
+func (s *mfaService) Status(userID uuid.UUID) (*MFAStatus, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MFAStatus{
+		Enabled:   user.IsMFAEnabled(),
+		EnabledAt: user.TOTPEnabledAt,
+	}
+	if status.Enabled {
+		if status.RecoveryCodesRemaining, err = s.recoveryRepo.CountUnused(userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// BeginTOTPSetup generates a new secret for the user's authenticator app.
+// It only takes effect once ConfirmTOTPSetup sees a code generated from it.
+func (s *mfaService) BeginTOTPSetup(userID uuid.UUID) (*TOTPSetup, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsMFAEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPSecret = secret
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return &TOTPSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, s.issuer, user.Email),
+	}, nil
+}
+
+// ConfirmTOTPSetup enables two-factor authentication once the user proves
+// their authenticator works, and returns their first recovery codes
+func (s *mfaService) ConfirmTOTPSetup(userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsMFAEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrMFASetupNotStarted
+	}
+
+	if err := s.verifyTOTP(user, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.TOTPEnabledAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableTOTP turns two-factor authentication off after checking a TOTP or
+// recovery code
+func (s *mfaService) DisableTOTP(userID uuid.UUID, code string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.VerifyCode(user, code); err != nil {
+		return err
+	}
+
+	user.TOTPSecret = ""
+	user.TOTPEnabledAt = nil
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+	return s.recoveryRepo.DeleteForUser(userID)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after checking
+// a TOTP or recovery code
+func (s *mfaService) RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.VerifyCode(user, code); err != nil {
+		return nil, err
+	}
+	return s.replaceRecoveryCodes(userID)
+}
+
+// VerifyCode accepts a current TOTP code or an unused recovery code. Either
+// works only once.
+func (s *mfaService) VerifyCode(user *models.User, code string) error {
+	if !user.IsMFAEnabled() {
+		return ErrMFANotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits && isDigits(code) {
+		return s.verifyTOTP(user, code)
+	}
+
+	err := s.recoveryRepo.Use(user.ID, auth.HashOpaqueToken(normalizeRecoveryCode(code)))
+	if err == repository.ErrRecoveryCodeInvalid {
+		return ErrInvalidMFACode
+	}
+	return err
+}
+
+// verifyTOTP checks a code against the user's secret and records its time
+// step so it cannot be replayed
+func (s *mfaService) verifyTOTP(user *models.User, code string) error {
+	step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew)
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	fresh, err := s.userRepo.AdvanceTOTPStep(user.ID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidMFACode
+	}
+
+	// Keep the loaded user in step so saving it does not roll the step back
+	user.TOTPLastStep = step
+	return nil
+}
+
+// replaceRecoveryCodes stores the hashes of a new set of recovery codes and
+// returns the codes for the user to write down
+func (s *mfaService) replaceRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = auth.HashOpaqueToken(normalizeRecoveryCode(code))
+	}
+
+	if err := s.recoveryRepo.ReplaceForUser(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode returns a random code formatted as xxxxx-xxxxx
+func generateRecoveryCode() (string, error) {
+	var b strings.Builder
+	alphabetSize := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < recoveryCodeLength; i++ {
+		if i == recoveryCodeLength/2 {
+			b.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(recoveryCodeAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// normalizeRecoveryCode ignores case, dashes and spaces
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+)
+
+var (
+	ErrInvalidOrganizationName = errors.New("organization name must be between 1 and 100 characters")
+	ErrInvalidOrganizationRole = errors.New("role must be owner, admin or member")
+	ErrLastOrganizationOwner   = errors.New("an organization must keep at least one owner")
+	ErrGuestMembership         = errors.New("guests cannot join organizations")
+)
+
+const maxOrganizationNameLength = 100
+
+// OrganizationUpdate holds the organization fields to change; nil fields are
+// kept
+type OrganizationUpdate struct {
+	Name            *string
+	DefaultSettings *models.MeetingSettings
+}
+
+type OrganizationService interface {
+	CreateOrganization(userID uuid.UUID, name string, defaults *models.MeetingSettings) (*models.OrganizationMember, error)
+	GetOrganization(orgID, userID uuid.UUID) (*models.OrganizationMember, error)
+	ListOrganizations(userID uuid.UUID) ([]models.OrganizationMember, error)
+	UpdateOrganization(orgID, userID uuid.UUID, update OrganizationUpdate) (*models.OrganizationMember, error)
+	DeleteOrganization(orgID, userID uuid.UUID) error
+	ListMembers(orgID, userID uuid.UUID) ([]models.OrganizationMember, error)
+	AddMember(orgID, userID uuid.UUID, email string, role models.OrganizationRole) (*models.OrganizationMember, error)
+	UpdateMemberRole(orgID, userID, targetUserID uuid.UUID, role models.OrganizationRole) error
+	RemoveMember(orgID, userID, targetUserID uuid.UUID) error
+}
+
+type organizationService struct {
+	orgRepo  repository.OrganizationRepository
+	userRepo repository.UserRepository
+}
+
+func NewOrganizationService(orgRepo repository.OrganizationRepository, userRepo repository.UserRepository) OrganizationService {
+	return &organizationService{
+		orgRepo:  orgRepo,
+		userRepo: userRepo,
+	}
+}
+
+// CreateOrganization creates an organization with the user as its owner
+func (s *organizationService) CreateOrganization(
+	userID uuid.UUID,
+	name string,
+	defaults *models.MeetingSettings,
+) (*models.OrganizationMember, error) {
+	name, err := normalizeOrganizationName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsGuest {
+		return nil, ErrGuestMembership
+	}
+
+	org := &models.Organization{
+		Name:            name,
+		DefaultSettings: defaults,
+	}
+	if err := s.orgRepo.Create(org, userID); err != nil {
+		return nil, err
+	}
+
+	return s.orgRepo.FindMember(org.ID, userID)
+}
+
+// GetOrganization returns the user's membership of the organization. The
+// organization is not found for users outside it.
+func (s *organizationService) GetOrganization(orgID, userID uuid.UUID) (*models.OrganizationMember, error) {
+	member, err := s.orgRepo.FindMember(orgID, userID)
+	if err != nil {
+		if err == repository.ErrOrganizationMemberNotFound {
+			return nil, repository.ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *organizationService) ListOrganizations(userID uuid.UUID) ([]models.OrganizationMember, error) {
+	return s.orgRepo.FindMemberships(userID)
+}
+
+// UpdateOrganization changes the name and default meeting settings (owners
+// and admins)
+func (s *organizationService) UpdateOrganization(
+	orgID, userID uuid.UUID,
+	update OrganizationUpdate,
+) (*models.OrganizationMember, error) {
+	member, err := s.GetOrganization(orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.Role.CanManage() {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	org := &member.Organization
+	if update.Name != nil {
+		name, err := normalizeOrganizationName(*update.Name)
+		if err != nil {
+			return nil, err
+		}
+		org.Name = name
+	}
+	if update.DefaultSettings != nil {
+		org.DefaultSettings = update.DefaultSettings
+	}
+
+	if err := s.orgRepo.Update(org); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// DeleteOrganization deletes the organization (owners only). Its meetings
+// stay with their hosts and become public.
+func (s *organizationService) DeleteOrganization(orgID, userID uuid.UUID) error {
+	member, err := s.GetOrganization(orgID, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role != models.OrganizationRoleOwner {
+		return ErrUnauthorizedAccess
+	}
+
+	return s.orgRepo.Delete(orgID)
+}
+
+// ListMembers returns the members of the organization to any of its members
+func (s *organizationService) ListMembers(orgID, userID uuid.UUID) ([]models.OrganizationMember, error) {
+	if _, err := s.GetOrganization(orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.FindMembers(orgID)
+}
+
+// AddMember adds the user with the given email to the organization. Admins
+// can add members; only owners can add admins and owners.
+func (s *organizationService) AddMember(
+	orgID, userID uuid.UUID,
+	email string,
+	role models.OrganizationRole,
+) (*models.OrganizationMember, error) {
+	if role == "" {
+		role = models.OrganizationRoleMember
+	}
+	if !role.IsValid() {
+		return nil, ErrInvalidOrganizationRole
+	}
+
+	member, err := s.GetOrganization(orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.Role.CanManage() || (role != models.OrganizationRoleMember && member.Role != models.OrganizationRoleOwner) {
+		return nil, ErrUnauthorizedAccess
+	}
+
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(email))
+	if err != nil {
+		return nil, err
+	}
+	if user.IsGuest {
+		return nil, ErrGuestMembership
+	}
+
+	added := &models.OrganizationMember{
+		OrganizationID: orgID,
+		UserID:         user.ID,
+		Role:           role,
+	}
+	if err := s.orgRepo.AddMember(added); err != nil {
+		return nil, err
+	}
+
+	added.User = *user
+	return added, nil
+}
+
+// UpdateMemberRole changes a member's role (owners only). The last owner
+// cannot step down.
+func (s *organizationService) UpdateMemberRole(
+	orgID, userID, targetUserID uuid.UUID,
+	role models.OrganizationRole,
+) error {
+	if !role.IsValid() {
+		return ErrInvalidOrganizationRole
+	}
+
+	member, err := s.GetOrganization(orgID, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role != models.OrganizationRoleOwner {
+		return ErrUnauthorizedAccess
+	}
+
+	target, err := s.orgRepo.FindMember(orgID, targetUserID)
+	if err != nil {
+		return err
+	}
+	if target.Role == models.OrganizationRoleOwner && role != models.OrganizationRoleOwner {
+		if err := s.ensureAnotherOwner(orgID); err != nil {
+			return err
+		}
+	}
+
+	return s.orgRepo.UpdateMemberRole(orgID, targetUserID, role)
+}
+
+// RemoveMember removes a member from the organization. Members can leave on
+// their own, admins can remove members and owners can remove anyone, but the
+// last owner cannot be removed.
+func (s *organizationService) RemoveMember(orgID, userID, targetUserID uuid.UUID) error {
+	member, err := s.GetOrganization(orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	target := member
+	if targetUserID != userID {
+		if target, err = s.orgRepo.FindMember(orgID, targetUserID); err != nil {
+			return err
+		}
+
+		allowed := member.Role == models.OrganizationRoleOwner ||
+			(member.Role == models.OrganizationRoleAdmin && target.Role == models.OrganizationRoleMember)
+		if !allowed {
+			return ErrUnauthorizedAccess
+		}
+	}
+
+	if target.Role == models.OrganizationRoleOwner {
+		if err := s.ensureAnotherOwner(orgID); err != nil {
+			return err
+		}
+	}
+
+	return s.orgRepo.RemoveMember(orgID, targetUserID)
+}
+
+// ensureAnotherOwner fails when the organization has a single owner left
+func (s *organizationService) ensureAnotherOwner(orgID uuid.UUID) error {
+	owners, err := s.orgRepo.CountOwners(orgID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOrganizationOwner
+	}
+	return nil
+}
+
+func normalizeOrganizationName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxOrganizationNameLength {
+		return "", ErrInvalidOrganizationName
+	}
+	return name, nil
+}
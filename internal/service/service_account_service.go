@@ -0,0 +1,79 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+)
+
+type ServiceAccountService interface {
+	CreateServiceAccount(ownerID uuid.UUID, name, username string) (*models.User, error)
+	ListServiceAccounts(ownerID uuid.UUID) ([]models.User, error)
+	DeleteServiceAccount(ownerID, id uuid.UUID) error
+}
+
+type serviceAccountService struct {
+	userRepo  repository.UserRepository
+	tokenRepo repository.APITokenRepository
+}
+
+func NewServiceAccountService(userRepo repository.UserRepository, tokenRepo repository.APITokenRepository) ServiceAccountService {
+	return &serviceAccountService{
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+	}
+}
+
+// CreateServiceAccount creates a credential-less user owned by ownerID. It
+// authenticates only with API tokens its owner creates for it.
+func (s *serviceAccountService) CreateServiceAccount(ownerID uuid.UUID, name, username string) (*models.User, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxNameLength {
+		return nil, ErrInvalidName
+	}
+	username = strings.TrimSpace(username)
+	if length := len([]rune(username)); length < minUsernameLength || length > maxUsernameLength {
+		return nil, ErrInvalidUsername
+	}
+
+	id := uuid.New()
+	account := &models.User{
+		ID:               id,
+		Email:            id.String() + "@service.invalid",
+		Username:         username,
+		Name:             name,
+		Role:             models.UserRoleMember,
+		IsServiceAccount: true,
+		OwnerID:          &ownerID,
+	}
+	if err := s.userRepo.Create(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func (s *serviceAccountService) ListServiceAccounts(ownerID uuid.UUID) ([]models.User, error) {
+	return s.userRepo.FindServiceAccountsByOwner(ownerID)
+}
+
+// DeleteServiceAccount revokes the account's tokens and deletes it. Meetings
+// it hosted are kept.
+func (s *serviceAccountService) DeleteServiceAccount(ownerID, id uuid.UUID) error {
+	account, err := s.userRepo.FindByID(id)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return ErrServiceAccountNotFound
+		}
+		return err
+	}
+	if !account.IsServiceAccount || account.OwnerID == nil || *account.OwnerID != ownerID {
+		return ErrServiceAccountNotFound
+	}
+
+	if err := s.tokenRepo.RevokeAllForUser(id); err != nil {
+		return err
+	}
+	return s.userRepo.Delete(id)
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/meet-app/backend/internal/config"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/pkg/auth"
+	"github.com/meet-app/backend/pkg/oidc"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrSSODisabled          = errors.New("single sign-on is not configured")
+	ErrSSOInvalidState      = errors.New("sign-in request is invalid or has expired")
+	ErrSSOEmailNotVerified  = errors.New("identity provider has not verified the email address")
+	ErrSSONoAccount         = errors.New("no account exists for this email address")
+	ErrSSOAccountUnverified = errors.New("verify the email address of your existing account before signing in with single sign-on")
+)
+
+const (
+	// How long a started login may take to come back from the provider
+	ssoStateTTL    = 10 * time.Minute
+	ssoStatePrefix = "oidc:state:"
+
+	// Time allowed for the provider round trips of a single login
+	ssoRequestTimeout = 15 * time.Second
+
+	usernameAttempts = 5
+)
+
+// ssoState is what a started login keeps until the provider redirects back
+type ssoState struct {
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+}
+
+type SSOService interface {
+	BeginLogin() (string, error)
+	CompleteLogin(code, state string, client ClientInfo) (*models.User, *auth.TokenPair, error)
+}
+
+type ssoService struct {
+	provider     *oidc.Provider
+	userRepo     repository.UserRepository
+	identityRepo repository.UserIdentityRepository
+	loginRepo    repository.LoginAttemptRepository
+	audit        AuditService
+	challenges   *MFAChallengeStore
+	redis        *redis.Client
+	groupRoles   map[string]models.UserRole
+	oidcCfg      *config.OIDCConfig
+	keys         *auth.Keyring
+	jwtCfg       *config.JWTConfig
+}
+
+// NewSSOService creates the single sign-on flow. provider is nil when no
+// identity provider is configured.
+func NewSSOService(
+	provider *oidc.Provider,
+	userRepo repository.UserRepository,
+	identityRepo repository.UserIdentityRepository,
+	loginRepo repository.LoginAttemptRepository,
+	audit AuditService,
+	challenges *MFAChallengeStore,
+	client *redis.Client,
+	oidcCfg *config.OIDCConfig,
+	keys *auth.Keyring,
+	jwtCfg *config.JWTConfig,
+) (SSOService, error) {
+	groupRoles, err := parseGroupRoles(oidcCfg.GroupRoles, oidcCfg.GroupAdmins)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ssoService{
+		provider:     provider,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		loginRepo:    loginRepo,
+		audit:        audit,
+		challenges:   challenges,
+		redis:        client,
+		groupRoles:   groupRoles,
+		oidcCfg:      oidcCfg,
+		keys:         keys,
+		jwtCfg:       jwtCfg,
+	}, nil
+}
+
+// BeginLogin stores a fresh state, nonce and PKCE verifier and returns the
+// provider URL to send the browser to
+func (s *ssoService) BeginLogin() (string, error) {
+	if s.provider == nil {
+		return "", ErrSSODisabled
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ssoRequestTimeout)
+	defer cancel()
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(ssoState{Verifier: verifier, Nonce: nonce})
+	if err != nil {
+		return "", err
+	}
+	if err := s.redis.Set(ctx, ssoStatePrefix+state, data, ssoStateTTL).Err(); err != nil {
+		return "", err
+	}
+
+	return s.provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(verifier))
+}
+
+// CompleteLogin redeems the provider's authorization code, finds or creates
+// the user and issues our own tokens. Users with two-factor authentication
+// get an *MFARequiredError to finish at /auth/login/mfa instead.
+func (s *ssoService) CompleteLogin(code, state string, client ClientInfo) (*models.User, *auth.TokenPair, error) {
+	if s.provider == nil {
+		return nil, nil, ErrSSODisabled
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ssoRequestTimeout)
+	defer cancel()
+
+	// States are single-use
+	data, err := s.redis.GetDel(ctx, ssoStatePrefix+state).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil, ErrSSOInvalidState
+		}
+		return nil, nil, err
+	}
+	var pending ssoState
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, nil, ErrSSOInvalidState
+	}
+
+	rawIDToken, err := s.provider.Exchange(ctx, code, pending.Verifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	idToken, err := s.provider.VerifyIDToken(ctx, rawIDToken, pending.Nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attempt := &models.LoginAttempt{
+		Email:     idToken.Email,
+		IPAddress: client.IPAddress,
+		UserAgent: truncate(client.UserAgent, maxUserAgentLength),
+		Method:    models.LoginMethodSSO,
+	}
+
+	user, err := s.resolveUser(idToken)
+	if err != nil {
+		if err == ErrSSONoAccount || err == ErrSSOEmailNotVerified || err == ErrSSOAccountUnverified {
+			attempt.FailureReason = models.LoginFailureSSORejected
+			s.recordLogin(attempt, client)
+		}
+		return nil, nil, err
+	}
+
+	attempt.UserID = &user.ID
+	attempt.Email = user.Email
+	if user.IsSuspended() {
+		attempt.FailureReason = models.LoginFailureSuspended
+		s.recordLogin(attempt, client)
+		return nil, nil, ErrAccountSuspended
+	}
+
+	if err := s.syncRole(user, idToken); err != nil {
+		return nil, nil, err
+	}
+
+	// The provider does not replace the user's own second factor
+	if user.IsMFAEnabled() {
+		challenge, err := s.challenges.Create(ctx, user.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		attempt.FailureReason = models.LoginFailureMFARequired
+		s.recordLogin(attempt, client)
+		return nil, nil, challenge
+	}
+
+	attempt.Success = true
+	s.recordLogin(attempt, client)
+
+	tokens, err := auth.GenerateTokenPair(user.ID, user.Email, user.Username, s.keys, s.jwtCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// resolveUser returns the user linked to the provider account. Unlinked
+// accounts are linked to the user with the same verified email, or to a new
+// user if sign-up is allowed. Accounts whose owner has not verified the
+// email are never linked: whoever registered the address first may not be
+// its owner, and would keep their password on the account.
+func (s *ssoService) resolveUser(idToken *oidc.IDToken) (*models.User, error) {
+	identity, err := s.identityRepo.FindBySubject(idToken.Issuer, idToken.Subject)
+	if err == nil {
+		user, err := s.userRepo.FindByID(identity.UserID)
+		if err != nil {
+			if err == repository.ErrUserNotFound {
+				return nil, ErrSSONoAccount
+			}
+			return nil, err
+		}
+		if idToken.Email != "" && identity.Email != idToken.Email {
+			identity.Email = idToken.Email
+			if err := s.identityRepo.Update(identity); err != nil {
+				return nil, err
+			}
+		}
+		return user, nil
+	}
+	if err != repository.ErrIdentityNotFound {
+		return nil, err
+	}
+
+	// Linking by email is only safe if the provider vouches for the address
+	if idToken.Email == "" || !idToken.EmailVerified {
+		return nil, ErrSSOEmailNotVerified
+	}
+
+	user, err := s.userRepo.FindByEmail(idToken.Email)
+	switch {
+	case err == nil:
+		if user.IsGuest {
+			return nil, ErrSSONoAccount
+		}
+		if !user.IsEmailVerified() {
+			return nil, ErrSSOAccountUnverified
+		}
+	case err == repository.ErrUserNotFound:
+		if !s.oidcCfg.AllowSignup {
+			return nil, ErrSSONoAccount
+		}
+		if user, err = s.provisionUser(idToken); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if err := s.identityRepo.Create(&models.UserIdentity{
+		UserID:  user.ID,
+		Issuer:  idToken.Issuer,
+		Subject: idToken.Subject,
+		Email:   idToken.Email,
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// provisionUser creates a password-less account from the ID token's profile
+func (s *ssoService) provisionUser(idToken *oidc.IDToken) (*models.User, error) {
+	username, err := s.availableUsername(idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(idToken.Name)
+	if name == "" {
+		name = username
+	}
+
+	now := time.Now()
+	user := &models.User{
+		Email:           idToken.Email,
+		Username:        username,
+		Name:            name,
+		Role:            models.UserRoleMember,
+		EmailVerifiedAt: &now,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// availableUsername derives a free username from the preferred username or
+// the email's local part, adding a random suffix on collisions
+func (s *ssoService) availableUsername(idToken *oidc.IDToken) (string, error) {
+	base := sanitizeUsername(idToken.PreferredUsername)
+	if len(base) < minUsernameLength {
+		base = sanitizeUsername(strings.SplitN(idToken.Email, "@", 2)[0])
+	}
+	if len(base) < minUsernameLength {
+		base = "user"
+	}
+	// Leave room for a -NNNN suffix
+	if len(base) > maxUsernameLength-5 {
+		base = base[:maxUsernameLength-5]
+	}
+
+	candidate := base
+	for attempt := 0; attempt < usernameAttempts; attempt++ {
+		exists, err := s.userRepo.ExistsByUsername(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+
+		n, err := rand.Int(rand.Reader, big.NewInt(10000))
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s-%04d", base, n.Int64())
+	}
+	return "", repository.ErrUsernameAlreadyExists
+}
+
+// syncRole applies the group-to-role mapping on every login so changes at
+// the provider take effect. Without a mapping roles are managed here.
+func (s *ssoService) syncRole(user *models.User, idToken *oidc.IDToken) error {
+	if len(s.groupRoles) == 0 {
+		return nil
+	}
+
+	role := models.UserRoleMember
+	for _, group := range idToken.StringList(s.oidcCfg.GroupsClaim) {
+		if s.groupRoles[group] == models.UserRoleAdmin {
+			role = models.UserRoleAdmin
+			break
+		}
+	}
+
+	if user.Role == role {
+		return nil
+	}
+	user.Role = role
+	return s.userRepo.Update(user)
+}
+
+func (s *ssoService) recordLogin(attempt *models.LoginAttempt, client ClientInfo) {
+	if err := s.loginRepo.Create(attempt); err != nil {
+		log.Printf("SSO: failed to record login attempt for %s: %v", attempt.Email, err)
+	}
+	if event := loginAuditEvent(attempt); event != nil {
+		s.audit.Record(event, client)
+	}
+}
+
+// parseGroupRoles parses group=role pairs
+func parseGroupRoles(pairs []string, allowAdmin bool) (map[string]models.UserRole, error) {
+	groupRoles := make(map[string]models.UserRole, len(pairs))
+	for _, pair := range pairs {
+		group, role, ok := strings.Cut(pair, "=")
+		group, role = strings.TrimSpace(group), strings.TrimSpace(role)
+		if !ok || group == "" || !models.UserRole(role).IsValid() {
+			return nil, fmt.Errorf("invalid group role mapping %q", pair)
+		}
+		if models.UserRole(role) == models.UserRoleAdmin && !allowAdmin {
+			return nil, fmt.Errorf("group role mapping %q grants admin; set OIDC_GROUP_ADMINS=true to allow it", pair)
+		}
+		groupRoles[group] = models.UserRole(role)
+	}
+	return groupRoles, nil
+}
+
+// sanitizeUsername keeps the characters that are safe in a username
+func sanitizeUsername(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '_' || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
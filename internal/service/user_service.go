@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/avatar"
+	"github.com/meet-app/backend/internal/config"
+	"github.com/meet-app/backend/internal/models"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/pkg/auth"
+)
+
+var (
+	ErrIncorrectPassword   = errors.New("current password is incorrect")
+	ErrInvalidUsername     = errors.New("username must be between 3 and 30 characters")
+	ErrInvalidName         = errors.New("name must be between 1 and 100 characters")
+	ErrInvalidAvatarURL    = errors.New("avatar URL must be an http or https URL")
+	ErrInvalidAvatarUpload = errors.New("invalid avatar upload")
+)
+
+const (
+	minUsernameLength = 3
+	maxUsernameLength = 30
+	maxNameLength     = 100
+
+	// Largest avatar upload accepted for resizing
+	maxAvatarBytes = 5 << 20
+
+	// Lifetime of a presigned avatar upload URL
+	avatarUploadExpiry = 15 * time.Minute
+
+	// Time allowed to fetch, resize and store an avatar
+	avatarProcessTimeout = 30 * time.Second
+)
+
+// avatarSizes are the square thumbnail sizes generated for each avatar; the
+// largest becomes the user's avatar URL
+var avatarSizes = []int{64, 128, 256}
+
+// ObjectStorage stores uploaded files, e.g. in MinIO
+type ObjectStorage interface {
+	PresignPut(bucket, key string, expires time.Duration) (string, error)
+	GetObject(ctx context.Context, bucket, key string, maxBytes int64) ([]byte, error)
+	PutObject(ctx context.Context, bucket, key, contentType string, data []byte) error
+	ObjectURL(bucket, key string) string
+}
+
+// ProfileUpdate holds the profile fields to change; nil fields are kept
+type ProfileUpdate struct {
+	Name      *string
+	Username  *string
+	AvatarURL *string
+}
+
+// AvatarUpload is a presigned URL the client PUTs the original image to
+type AvatarUpload struct {
+	UploadURL string
+	ObjectKey string
+	ExpiresAt time.Time
+	MaxBytes  int64
+}
+
+type UserService interface {
+	UpdateProfile(userID uuid.UUID, update ProfileUpdate) (*models.User, error)
+	ChangePassword(userID uuid.UUID, currentPassword, newPassword string) (*auth.TokenPair, error)
+	CreateAvatarUpload(userID uuid.UUID) (*AvatarUpload, error)
+	CompleteAvatarUpload(userID uuid.UUID, objectKey string) (*models.User, map[int]string, error)
+}
+
+type userService struct {
+	userRepo     repository.UserRepository
+	revocations  *auth.RevocationStore
+	storage      ObjectStorage
+	avatarBucket string
+	keys         *auth.Keyring
+	jwtCfg       *config.JWTConfig
+}
+
+func NewUserService(
+	userRepo repository.UserRepository,
+	revocations *auth.RevocationStore,
+	storage ObjectStorage,
+	minioCfg *config.MinIOConfig,
+	keys *auth.Keyring,
+	jwtCfg *config.JWTConfig,
+) UserService {
+	return &userService{
+		userRepo:     userRepo,
+		revocations:  revocations,
+		storage:      storage,
+		avatarBucket: minioCfg.AvatarBucket,
+		keys:         keys,
+		jwtCfg:       jwtCfg,
+	}
+}
+
+// UpdateProfile changes the user's name, username and avatar URL
+func (s *userService) UpdateProfile(userID uuid.UUID, update ProfileUpdate) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" || len([]rune(name)) > maxNameLength {
+			return nil, ErrInvalidName
+		}
+		user.Name = name
+	}
+
+	if update.Username != nil {
+		username := strings.TrimSpace(*update.Username)
+		if length := len([]rune(username)); length < minUsernameLength || length > maxUsernameLength {
+			return nil, ErrInvalidUsername
+		}
+
+		if username != user.Username {
+			exists, err := s.userRepo.ExistsByUsername(username)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				return nil, repository.ErrUsernameAlreadyExists
+			}
+			user.Username = username
+		}
+	}
+
+	if update.AvatarURL != nil {
+		avatarURL := strings.TrimSpace(*update.AvatarURL)
+		if avatarURL != "" && !isHTTPURL(avatarURL) {
+			return nil, ErrInvalidAvatarURL
+		}
+		user.AvatarURL = avatarURL
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ChangePassword replaces the user's password and signs out every other
+// session. The returned tokens keep the current session signed in.
+func (s *userService) ChangePassword(userID uuid.UUID, currentPassword, newPassword string) (*auth.TokenPair, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := auth.VerifyPassword(user.Password, currentPassword); err != nil {
+		return nil, ErrIncorrectPassword
+	}
+	if !auth.IsPasswordValid(newPassword) {
+		return nil, ErrWeakPassword
+	}
+
+	hashedPassword, err := auth.HashPassword(newPassword)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = hashedPassword
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.revocations.RevokeBefore(context.Background(), user.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return auth.GenerateTokenPair(user.ID, user.Email, user.Username, s.keys, s.jwtCfg)
+}
+
+// CreateAvatarUpload returns a presigned URL to upload a new avatar image to
+func (s *userService) CreateAvatarUpload(userID uuid.UUID) (*AvatarUpload, error) {
+	key := fmt.Sprintf("%s/%s/original", userID, uuid.New())
+
+	uploadURL, err := s.storage.PresignPut(s.avatarBucket, key, avatarUploadExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AvatarUpload{
+		UploadURL: uploadURL,
+		ObjectKey: key,
+		ExpiresAt: time.Now().Add(avatarUploadExpiry),
+		MaxBytes:  maxAvatarBytes,
+	}, nil
+}
+
+// CompleteAvatarUpload resizes an uploaded avatar into thumbnails and makes
+// the largest one the user's avatar. It returns the URL of every size.
+func (s *userService) CompleteAvatarUpload(userID uuid.UUID, objectKey string) (*models.User, map[int]string, error) {
+	prefix, ok := avatarKeyPrefix(userID, objectKey)
+	if !ok {
+		return nil, nil, ErrInvalidAvatarUpload
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), avatarProcessTimeout)
+	defer cancel()
+
+	original, err := s.storage.GetObject(ctx, s.avatarBucket, objectKey, maxAvatarBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	thumbnails, err := avatar.Thumbnails(original, avatarSizes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	urls := make(map[int]string, len(thumbnails))
+	for _, size := range avatarSizes {
+		key := fmt.Sprintf("%s/%d.jpg", prefix, size)
+		if err := s.storage.PutObject(ctx, s.avatarBucket, key, "image/jpeg", thumbnails[size]); err != nil {
+			return nil, nil, err
+		}
+		urls[size] = s.storage.ObjectURL(s.avatarBucket, key)
+	}
+
+	user.AvatarURL = urls[avatarSizes[len(avatarSizes)-1]]
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, nil, err
+	}
+	return user, urls, nil
+}
+
+// avatarKeyPrefix checks that objectKey is an upload key issued to the user
+// and returns the folder its thumbnails go to
+func avatarKeyPrefix(userID uuid.UUID, objectKey string) (string, bool) {
+	parts := strings.Split(objectKey, "/")
+	if len(parts) != 3 || parts[0] != userID.String() || parts[2] != "original" {
+		return "", false
+	}
+	if _, err := uuid.Parse(parts[1]); err != nil {
+		return "", false
+	}
+	return parts[0] + "/" + parts[1], true
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -8,17 +8,22 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/repository"
 )
 
 // Handler handles SSE connections
 type Handler struct {
-	hub *Hub
+	hub             *Hub
+	meetingRepo     repository.MeetingRepository
+	participantRepo repository.ParticipantRepository
 }
 
 // NewHandler creates a new SSE handler
-func NewHandler() *Handler {
+func NewHandler(meetingRepo repository.MeetingRepository, participantRepo repository.ParticipantRepository) *Handler {
 	return &Handler{
-		hub: GetHub(),
+		hub:             GetHub(),
+		meetingRepo:     meetingRepo,
+		participantRepo: participantRepo,
 	}
 }
 
@@ -39,6 +44,34 @@ func (h *Handler) Stream(c *gin.Context) {
 		return
 	}
 
+	meeting, err := h.meetingRepo.FindByID(meetingID)
+	if err != nil {
+		if err == repository.ErrMeetingNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get meeting"})
+		return
+	}
+
+	// Only the host and those in the meeting hear it, so people waiting to
+	// be admitted (or who only know the meeting ID) cannot follow its chat
+	if meeting.HostID != userID {
+		participant, err := h.participantRepo.FindByUserAndMeeting(userID, meetingID)
+		if err != nil && err != repository.ErrParticipantNotFound {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get participant"})
+			return
+		}
+		if participant == nil || participant.LeftAt != nil {
+			message := "Join the meeting to receive its events"
+			if middleware.IsGuestFromContext(c) {
+				message = "Waiting for the host to admit you"
+			}
+			c.JSON(http.StatusForbidden, gin.H{"error": message})
+			return
+		}
+	}
+
 	// Set headers for SSE
 	c.Writer.Header().Set("Content-Type", "text/event-stream")
 	c.Writer.Header().Set("Cache-Control", "no-cache")
@@ -17,10 +17,16 @@ const (
 	EventParticipantUpdated EventType = "participant_updated"
 	EventChatMessage        EventType = "chat_message"
 	EventMeetingEnded       EventType = "meeting_ended"
+	EventMeetingEndingSoon  EventType = "meeting_ending_soon"
 	EventRecordingStarted   EventType = "recording_started"
 	EventRecordingStopped   EventType = "recording_stopped"
 	EventScreenShareStarted EventType = "screen_share_started"
 	EventScreenShareStopped EventType = "screen_share_stopped"
+	EventMention            EventType = "mention"
+	EventReactionAdded      EventType = "reaction_added"
+	EventReactionRemoved    EventType = "reaction_removed"
+	EventMessageFlagged     EventType = "message_flagged"
+	EventMessageDeleted     EventType = "message_deleted"
 )
 
 // Event represents an SSE event
@@ -138,6 +144,54 @@ func (h *Hub) BroadcastToMeetingExcept(meetingID uuid.UUID, excludeUserID uuid.U
 		event.Type, sentCount, meetingID, excludeUserID)
 }
 
+// SendToUser sends an event only to the clients of one user in a meeting
+func (h *Hub) SendToUser(meetingID uuid.UUID, userID uuid.UUID, event Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	clients, ok := h.clients[meetingID]
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("SSE: Failed to marshal event: %v", err)
+		return
+	}
+
+	for _, client := range clients {
+		if client.UserID != userID {
+			continue
+		}
+		select {
+		case client.Send <- data:
+		default:
+			log.Printf("SSE: Client buffer full, skipping UserID: %s", client.UserID)
+		}
+	}
+}
+
+// DisconnectUser ends every event stream of the user, in all meetings
+func (h *Hub) DisconnectUser(userID uuid.UUID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for meetingID, clients := range h.clients {
+		for id, client := range clients {
+			if client.UserID != userID {
+				continue
+			}
+			// The stream ends when it finds its channel closed
+			delete(clients, id)
+			close(client.Send)
+		}
+		if len(clients) == 0 {
+			delete(h.clients, meetingID)
+		}
+	}
+}
+
 // GetClientCount returns the number of connected clients for a meeting
 func (h *Hub) GetClientCount(meetingID uuid.UUID) int {
 	h.mu.RLock()
@@ -0,0 +1,57 @@
+package transcript
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/meet-app/backend/internal/models"
+)
+
+type csvWriter struct {
+	w   *csv.Writer
+	loc *time.Location
+}
+
+func newCSVWriter(w io.Writer, loc *time.Location) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w), loc: loc}
+}
+
+func (c *csvWriter) Begin(meeting *models.Meeting) error {
+	return c.w.Write([]string{
+		"id", "timestamp", "author_name", "author_username", "type",
+		"content", "file_url", "reply_to", "private",
+	})
+}
+
+func (c *csvWriter) Write(message *models.Message) error {
+	replyTo := ""
+	if message.ParentID != nil {
+		replyTo = message.ParentID.String()
+	}
+
+	err := c.w.Write([]string{
+		message.ID.String(),
+		message.CreatedAt.In(c.loc).Format(time.RFC3339),
+		authorName(message),
+		message.User.Username,
+		string(message.Type),
+		message.Content,
+		message.FileURL,
+		replyTo,
+		strconv.FormatBool(message.IsPrivate()),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Flush as we go so rows are streamed to the client
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) End() error {
+	c.w.Flush()
+	return c.w.Error()
+}
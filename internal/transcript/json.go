@@ -0,0 +1,93 @@
+package transcript
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/models"
+)
+
+// jsonWriter streams {"meeting": {...}, "messages": [...]} one message at a time
+type jsonWriter struct {
+	w     io.Writer
+	loc   *time.Location
+	count int
+}
+
+type jsonMeeting struct {
+	models.MeetingSummaryResponse
+	HostName string `json:"host_name"`
+	TimeZone string `json:"time_zone"`
+}
+
+type jsonMessage struct {
+	ID             uuid.UUID          `json:"id"`
+	Timestamp      string             `json:"timestamp"`
+	AuthorID       uuid.UUID          `json:"author_id"`
+	AuthorName     string             `json:"author_name"`
+	AuthorUsername string             `json:"author_username"`
+	Type           models.MessageType `json:"type"`
+	Content        string             `json:"content"`
+	FileURL        string             `json:"file_url,omitempty"`
+	ReplyTo        *uuid.UUID         `json:"reply_to,omitempty"`
+	RecipientID    *uuid.UUID         `json:"recipient_id,omitempty"`
+}
+
+func newJSONWriter(w io.Writer, loc *time.Location) *jsonWriter {
+	return &jsonWriter{w: w, loc: loc}
+}
+
+func (j *jsonWriter) Begin(meeting *models.Meeting) error {
+	header, err := json.Marshal(jsonMeeting{
+		MeetingSummaryResponse: meeting.ToSummaryResponse(),
+		HostName:               meeting.Host.Name,
+		TimeZone:               j.loc.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(j.w, `{"meeting":`); err != nil {
+		return err
+	}
+	if _, err := j.w.Write(header); err != nil {
+		return err
+	}
+	_, err = io.WriteString(j.w, `,"messages":[`)
+	return err
+}
+
+func (j *jsonWriter) Write(message *models.Message) error {
+	data, err := json.Marshal(jsonMessage{
+		ID:             message.ID,
+		Timestamp:      message.CreatedAt.In(j.loc).Format(time.RFC3339),
+		AuthorID:       message.UserID,
+		AuthorName:     authorName(message),
+		AuthorUsername: message.User.Username,
+		Type:           message.Type,
+		Content:        message.Content,
+		FileURL:        message.FileURL,
+		ReplyTo:        message.ParentID,
+		RecipientID:    message.RecipientID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if j.count > 0 {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	j.count++
+
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonWriter) End() error {
+	_, err := io.WriteString(j.w, "]}\n")
+	return err
+}
@@ -0,0 +1,75 @@
+package transcript
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/meet-app/backend/internal/models"
+)
+
+type markdownWriter struct {
+	w   io.Writer
+	loc *time.Location
+}
+
+func (m *markdownWriter) Begin(meeting *models.Meeting) error {
+	_, err := fmt.Fprintf(m.w, "# %s\n\n- Code: `%s`\n- Host: %s\n%s\n## Chat\n\n",
+		meeting.Title, meeting.Code, meeting.Host.Name, meetingTimes(meeting, m.loc, "- "))
+	return err
+}
+
+func (m *markdownWriter) Write(message *models.Message) error {
+	timestamp := message.CreatedAt.In(m.loc).Format(timeLayout)
+
+	if message.Type == models.MessageTypeSystem {
+		_, err := fmt.Fprintf(m.w, "_%s — %s_\n\n", timestamp, singleLine(message.Content))
+		return err
+	}
+
+	var labels []string
+	if message.IsPrivate() {
+		labels = append(labels, "private")
+	}
+	if message.ParentID != nil {
+		labels = append(labels, "reply")
+	}
+	label := ""
+	if len(labels) > 0 {
+		label = " (" + strings.Join(labels, ", ") + ")"
+	}
+
+	if _, err := fmt.Fprintf(m.w, "**%s** · %s%s\n\n", authorName(message), timestamp, label); err != nil {
+		return err
+	}
+
+	// Quote every line so multi-line messages stay grouped
+	quoted := "> " + strings.ReplaceAll(message.Content, "\n", "\n> ")
+	if _, err := fmt.Fprintf(m.w, "%s\n\n", quoted); err != nil {
+		return err
+	}
+
+	if message.FileURL != "" {
+		if _, err := fmt.Fprintf(m.w, "[Attachment](%s)\n\n", message.FileURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *markdownWriter) End() error {
+	return nil
+}
+
+// meetingTimes renders the meeting's start and end lines, each with prefix
+func meetingTimes(meeting *models.Meeting, loc *time.Location, prefix string) string {
+	var b strings.Builder
+	if meeting.StartedAt != nil {
+		fmt.Fprintf(&b, "%sStarted: %s\n", prefix, meeting.StartedAt.In(loc).Format(timeLayout))
+	}
+	if meeting.EndedAt != nil {
+		fmt.Fprintf(&b, "%sEnded: %s\n", prefix, meeting.EndedAt.In(loc).Format(timeLayout))
+	}
+	return b.String()
+}
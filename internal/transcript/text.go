@@ -0,0 +1,46 @@
+package transcript
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/meet-app/backend/internal/models"
+)
+
+type textWriter struct {
+	w   io.Writer
+	loc *time.Location
+}
+
+func (t *textWriter) Begin(meeting *models.Meeting) error {
+	_, err := fmt.Fprintf(t.w, "%s\nCode: %s\nHost: %s\n%s\n",
+		meeting.Title, meeting.Code, meeting.Host.Name, meetingTimes(meeting, t.loc, ""))
+	return err
+}
+
+func (t *textWriter) Write(message *models.Message) error {
+	timestamp := message.CreatedAt.In(t.loc).Format(timeLayout)
+
+	if message.Type == models.MessageTypeSystem {
+		_, err := fmt.Fprintf(t.w, "[%s] * %s\n", timestamp, singleLine(message.Content))
+		return err
+	}
+
+	author := authorName(message)
+	if message.IsPrivate() {
+		author += " (private)"
+	}
+
+	line := singleLine(message.Content)
+	if message.FileURL != "" {
+		line += " <" + message.FileURL + ">"
+	}
+
+	_, err := fmt.Fprintf(t.w, "[%s] %s: %s\n", timestamp, author, line)
+	return err
+}
+
+func (t *textWriter) End() error {
+	return nil
+}
@@ -0,0 +1,81 @@
+package transcript
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/meet-app/backend/internal/models"
+)
+
+// Format is a transcript export format
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatJSON     Format = "json"
+	FormatCSV      Format = "csv"
+	FormatText     Format = "txt"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported transcript format")
+
+// timeLayout is used for timestamps in the human-readable formats
+const timeLayout = "2006-01-02 15:04:05 MST"
+
+// Writer renders a meeting transcript incrementally
+type Writer interface {
+	// Begin writes the transcript header
+	Begin(meeting *models.Meeting) error
+	// Write renders one message
+	Write(message *models.Message) error
+	// End writes the transcript footer and flushes buffered output
+	End() error
+}
+
+// NewWriter creates a transcript writer for format that renders timestamps in loc
+func NewWriter(format Format, w io.Writer, loc *time.Location) (Writer, error) {
+	switch format {
+	case FormatMarkdown:
+		return &markdownWriter{w: w, loc: loc}, nil
+	case FormatJSON:
+		return newJSONWriter(w, loc), nil
+	case FormatCSV:
+		return newCSVWriter(w, loc), nil
+	case FormatText:
+		return &textWriter{w: w, loc: loc}, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// ContentType returns the MIME type for format
+func ContentType(format Format) string {
+	switch format {
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// authorName returns the display name used for a message author
+func authorName(message *models.Message) string {
+	if message.Type == models.MessageTypeSystem {
+		return "System"
+	}
+	if message.User.Name != "" {
+		return message.User.Name
+	}
+	return message.User.Username
+}
+
+// singleLine collapses newlines so a message fits on one transcript line
+func singleLine(content string) string {
+	return strings.Join(strings.Fields(content), " ")
+}
@@ -2,6 +2,7 @@ package websocket
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"time"
@@ -10,7 +11,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/meet-app/backend/internal/api/middleware"
+	"github.com/meet-app/backend/internal/models"
 	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/internal/service"
+	"github.com/meet-app/backend/internal/sse"
 )
 
 const (
@@ -39,15 +43,28 @@ var upgrader = websocket.Upgrader{
 
 // Handler handles WebSocket connections
 type Handler struct {
-	hub              *Hub
-	participantRepo  repository.ParticipantRepository
+	hub             *Hub
+	participantRepo repository.ParticipantRepository
+	meetingService  service.MeetingService
+	messageService  service.MessageService
+	auditService    service.AuditService
+	mediaState      *mediaStateRecorder
 }
 
 // NewHandler creates a new WebSocket handler
-func NewHandler(participantRepo repository.ParticipantRepository) *Handler {
+func NewHandler(
+	participantRepo repository.ParticipantRepository,
+	meetingService service.MeetingService,
+	messageService service.MessageService,
+	auditService service.AuditService,
+) *Handler {
 	return &Handler{
 		hub:             GetHub(),
 		participantRepo: participantRepo,
+		meetingService:  meetingService,
+		messageService:  messageService,
+		auditService:    auditService,
+		mediaState:      newMediaStateRecorder(meetingService, participantRepo),
 	}
 }
 
@@ -78,6 +95,28 @@ func (h *Handler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
+	// Ended meetings accept no new connections
+	meeting, err := h.meetingService.GetMeetingByID(meetingID)
+	if err != nil {
+		if err == repository.ErrMeetingNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Meeting not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get meeting"})
+		return
+	}
+	if meeting.Status == models.MeetingStatusEnded {
+		c.JSON(http.StatusGone, gin.H{"error": "Meeting has ended"})
+		return
+	}
+
+	// The host may have stopped allowing guests since the token was issued
+	isGuest := middleware.IsGuestFromContext(c)
+	if isGuest && !meeting.Settings.AllowGuests {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Meeting does not allow guests"})
+		return
+	}
+
 	// Upgrade HTTP connection to WebSocket
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
@@ -93,6 +132,13 @@ func (h *Handler) HandleWebSocket(c *gin.Context) {
 		MeetingID: meetingID,
 		Send:      make(chan []byte, 256),
 		Hub:       h.hub,
+		IsGuest:   isGuest,
+		info: service.ClientInfo{
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			RequestID: middleware.GetRequestIDFromContext(c),
+		},
+		closed: make(chan struct{}),
 	}
 
 	// Add to pending clients (not registered for WebRTC yet)
@@ -107,6 +153,15 @@ func (h *Handler) HandleWebSocket(c *gin.Context) {
 // readPump pumps messages from the WebSocket connection to the hub
 func (h *Handler) readPump(client *Client, conn *websocket.Conn) {
 	defer func() {
+		// The hub stops a disconnected user's screen share; record it in chat
+		if sharingUserID, isSharing := h.hub.GetScreenSharingUser(client.MeetingID); isSharing && sharingUserID == client.UserID {
+			h.mediaState.Record(client.MeetingID, client.UserID, MediaState{IsSharing: boolPtr(false)})
+			h.postSystemMessage(client, models.SystemMessageScreenShareStopped, nil)
+			h.recordAudit(client, models.AuditActionScreenShareStopped, nil, map[string]interface{}{"reason": "disconnected"})
+		}
+		h.mediaState.Flush(client.MeetingID, client.UserID)
+		h.closeParticipantSession(client)
+
 		// Remove from pending or registered clients
 		h.hub.RemovePendingClient(client)
 		h.hub.unregister <- client
@@ -155,6 +210,11 @@ func (h *Handler) writePump(client *Client, conn *websocket.Conn) {
 
 	for {
 		select {
+		case <-client.closed:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(client.closeCode, client.closeText))
+			return
+
 		case message, ok := <-client.Send:
 			conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if !ok {
@@ -189,8 +249,44 @@ func (h *Handler) writePump(client *Client, conn *websocket.Conn) {
 	}
 }
 
+// closeParticipantSession marks a participant whose connection dropped as
+// having left, closing their open attendance session. Nothing is recorded if
+// the user already left explicitly or has reconnected on another socket.
+func (h *Handler) closeParticipantSession(client *Client) {
+	if current := h.hub.GetClient(client.MeetingID, client.UserID); current != nil && current != client {
+		return
+	}
+	if pending := h.hub.GetPendingClient(client.MeetingID, client.UserID); pending != nil && pending != client {
+		return
+	}
+
+	err := h.meetingService.LeaveMeeting(client.UserID, client.MeetingID)
+	if err != nil {
+		if err != repository.ErrParticipantNotFound && err != service.ErrNotInMeeting {
+			log.Printf("WebSocket: Failed to close session for user %s in meeting %s: %v", client.UserID, client.MeetingID, err)
+		}
+		return
+	}
+
+	sse.GetHub().BroadcastToMeeting(client.MeetingID, sse.Event{
+		Type: sse.EventParticipantLeft,
+		Data: map[string]string{"user_id": client.UserID.String()},
+	})
+	h.postSystemMessage(client, models.SystemMessageParticipantLeft, nil)
+}
+
 // handleMessage handles incoming WebSocket messages
 func (h *Handler) handleMessage(client *Client, msg *Message) {
+	// Guests can neither admit themselves nor anyone else
+	if client.IsGuest {
+		switch msg.Type {
+		case MessageTypeHostJoin, MessageTypeApproveJoinRequest, MessageTypeRejectJoinRequest:
+			log.Printf("WebSocket: Guest %s sent %s, ignoring", client.UserID, msg.Type)
+			h.sendError(client, "Not allowed for guests")
+			return
+		}
+	}
+
 	switch msg.Type {
 	case MessageTypeOffer, MessageTypeAnswer, MessageTypeICECandidate:
 		// Forward signaling messages to the recipient
@@ -202,10 +298,8 @@ func (h *Handler) handleMessage(client *Client, msg *Message) {
 		h.hub.SendMessage(msg)
 
 	case MessageTypeMediaStateChanged:
-		// Broadcast media state changes to all other participants
-		log.Printf("WebSocket: User %s changed media state in meeting %s, data: %+v", client.UserID, client.MeetingID, msg.Data)
-		h.hub.SendMessage(msg)
-		log.Printf("WebSocket: Media state change broadcasted from %s", client.UserID)
+		// Persist and broadcast media state changes to all other participants
+		h.handleMediaStateChanged(client, msg)
 
 	case MessageTypeHostJoin:
 		// Handle host joining (auto-approve)
@@ -249,6 +343,25 @@ func (h *Handler) handleMessage(client *Client, msg *Message) {
 func (h *Handler) handleHostJoin(client *Client, msg *Message) {
 	log.Printf("WebSocket: Host %s joining meeting %s", client.UserID, client.MeetingID)
 
+	meeting, err := h.meetingService.GetMeetingByID(client.MeetingID)
+	if err != nil {
+		log.Printf("WebSocket: Failed to get meeting %s: %v", client.MeetingID, err)
+		h.sendError(client, "Failed to join meeting")
+		return
+	}
+	if meeting.HostID != client.UserID {
+		log.Printf("WebSocket: User %s sent host join but does not host meeting %s", client.UserID, client.MeetingID)
+		h.sendError(client, "Only the host can join as host")
+		return
+	}
+
+	// A host whose connection dropped is brought back into the meeting
+	if _, err := h.meetingService.AdmitParticipant(client.MeetingID, client.UserID); err != nil {
+		log.Printf("WebSocket: Failed to admit host %s to meeting %s: %v", client.UserID, client.MeetingID, err)
+		h.sendError(client, joinAccessErrorMessage(err))
+		return
+	}
+
 	// Move client from pending to registered (auto-approve for host)
 	h.hub.ApproveClient(client.MeetingID, client.UserID)
 
@@ -302,53 +415,46 @@ func (h *Handler) handleJoinRequest(client *Client, msg *Message) {
 
 	email, _ := data["email"].(string)
 
-	// Check if user has already joined this meeting before (re-join case)
-	_, err = h.participantRepo.FindByUserAndMeeting(client.UserID, client.MeetingID)
-	if err == nil {
-		// User has joined before - auto-approve without host confirmation
-		log.Printf("WebSocket: User %s re-joining meeting %s - auto-approving", client.UserID, client.MeetingID)
-
-		// Move client from pending to registered
-		h.hub.ApproveClient(client.MeetingID, client.UserID)
-
-		// Send approval to user
-		approvalMsg := &Message{
-			Type:      MessageTypeJoinApproved,
-			To:        client.UserID,
-			MeetingID: client.MeetingID,
-			Data: map[string]interface{}{
-				"message": "Auto-approved (returning user)",
-			},
+	// Guests passed the passcode check for their token and always wait for
+	// the host
+	if !client.IsGuest {
+		// Returning users and organization members are let straight in;
+		// first-time joiners need the passcode or an invite link
+		passcode, _ := data["passcode"].(string)
+		inviteToken, _ := data["invite_token"].(string)
+		creds := service.JoinCredentials{
+			Passcode:    passcode,
+			InviteToken: inviteToken,
 		}
-		h.hub.SendMessage(approvalMsg)
-
-		// Check if someone is currently sharing screen and notify the re-joining user
-		if sharingUserID, isSharing := h.hub.GetScreenSharingUser(client.MeetingID); isSharing {
-			// Get the sharing user's client to get username
-			sharingClient := h.hub.GetClient(client.MeetingID, sharingUserID)
-			if sharingClient != nil {
-				screenShareMsg := &Message{
-					Type:      MessageTypeScreenShareStarted,
-					From:      sharingUserID,
-					To:        client.UserID,
-					MeetingID: client.MeetingID,
-					Data: &ScreenShareInfo{
-						UserID:    sharingUserID,
-						Username:  sharingClient.Username,
-						Timestamp: time.Now().Unix(),
-					},
-				}
-				h.hub.SendMessage(screenShareMsg)
-				log.Printf("WebSocket: Sent screen share state to re-joining user %s", client.UserID)
-			}
+		grant, err := h.meetingService.VerifyJoinAccess(client.MeetingID, client.UserID, creds)
+		if err != nil {
+			log.Printf("WebSocket: User %s denied access to meeting %s: %v", client.UserID, client.MeetingID, err)
+			h.sendError(client, joinAccessErrorMessage(err))
+			return
 		}
 
-		log.Printf("WebSocket: User %s auto-approved for re-join", client.UserID)
-		return
+		if grant.BypassWaitingRoom {
+			// Joining uses up the invite, checks the meeting's capacity and
+			// records the participant; users who joined over REST first are
+			// in already
+			_, err := h.meetingService.JoinMeeting(client.UserID, client.MeetingID, models.ParticipantRoleGuest, creds)
+			if err != nil && err != service.ErrAlreadyInMeeting {
+				log.Printf("WebSocket: User %s could not join meeting %s: %v", client.UserID, client.MeetingID, err)
+				h.sendError(client, joinAccessErrorMessage(err))
+				return
+			}
+			if err := h.admitUser(client.MeetingID, client.UserID, grant.Reason); err != nil {
+				log.Printf("WebSocket: Failed to admit user %s to meeting %s: %v", client.UserID, client.MeetingID, err)
+				h.sendError(client, joinAccessErrorMessage(err))
+				return
+			}
+			log.Printf("WebSocket: User %s admitted to meeting %s: %s", client.UserID, client.MeetingID, grant.Reason)
+			return
+		}
 	}
 
-	// User is joining for the first time - require host approval
-	log.Printf("WebSocket: User %s joining meeting %s for first time - requiring approval", client.UserID, client.MeetingID)
+	// Everyone else waits in the waiting room for the host's approval
+	log.Printf("WebSocket: User %s joining meeting %s - requiring approval", client.UserID, client.MeetingID)
 
 	// Create join request info
 	joinRequest := &JoinRequestInfo{
@@ -399,6 +505,11 @@ func (h *Handler) handleJoinRequest(client *Client, msg *Message) {
 
 // handleApproveJoinRequest handles approval from host
 func (h *Handler) handleApproveJoinRequest(client *Client, msg *Message) {
+	if !h.canAdmit(client) {
+		h.sendError(client, "Only the host or moderators can admit users")
+		return
+	}
+
 	// Parse approval data
 	data, ok := msg.Data.(map[string]interface{})
 	if !ok {
@@ -429,34 +540,72 @@ func (h *Handler) handleApproveJoinRequest(client *Client, msg *Message) {
 		return
 	}
 
+	// Move client from pending to registered (approved for WebRTC); the
+	// request stays pending if the meeting has no room
+	if err := h.admitUser(client.MeetingID, requestUserID, "Your join request has been approved"); err != nil {
+		log.Printf("WebSocket: Failed to admit user %s to meeting %s: %v", requestUserID, client.MeetingID, err)
+		h.sendError(client, joinAccessErrorMessage(err))
+		return
+	}
+
 	// Remove from pending requests
 	h.hub.RemovePendingJoinRequest(client.MeetingID, requestUserID)
 
-	// Move client from pending to registered (approved for WebRTC)
-	// This will trigger peer-joined notification to all existing participants
-	h.hub.ApproveClient(client.MeetingID, requestUserID)
+	h.postSystemMessageFor(client.MeetingID, requestUserID, models.SystemMessageParticipantAdmitted, map[string]string{
+		"name": joinRequest.Username,
+		"by":   client.Username,
+	})
+	h.recordAudit(client, models.AuditActionJoinApproved, &requestUserID, map[string]interface{}{
+		"username": joinRequest.Username,
+	})
+
+	log.Printf("WebSocket: Host %s approved join request from %s (%s)", client.UserID, joinRequest.Username, requestUserID)
+}
+
+// canAdmit reports whether the client's user may answer join requests, i.e.
+// hosts or moderates the meeting
+func (h *Handler) canAdmit(client *Client) bool {
+	participant, err := h.participantRepo.FindByUserAndMeeting(client.UserID, client.MeetingID)
+	if err != nil {
+		if err != repository.ErrParticipantNotFound {
+			log.Printf("WebSocket: Failed to get participant %s in meeting %s: %v", client.UserID, client.MeetingID, err)
+		}
+		return false
+	}
+	return participant.CanModerate()
+}
+
+// admitUser records the user as a participant, rejoining them if their
+// connection dropped earlier, then moves them from the waiting room into the
+// meeting, which notifies the other participants, and sends them the
+// approval and current screen share state
+func (h *Handler) admitUser(meetingID, userID uuid.UUID, message string) error {
+	if _, err := h.meetingService.AdmitParticipant(meetingID, userID); err != nil {
+		return err
+	}
+
+	h.hub.ApproveClient(meetingID, userID)
 
-	// Send approval to requesting user
 	approvalMsg := &Message{
 		Type:      MessageTypeJoinApproved,
-		To:        requestUserID,
-		MeetingID: client.MeetingID,
+		To:        userID,
+		MeetingID: meetingID,
 		Data: map[string]interface{}{
-			"message": "Your join request has been approved",
+			"message": message,
 		},
 	}
 	h.hub.SendMessage(approvalMsg)
 
 	// Check if someone is currently sharing screen and notify the new user
-	if sharingUserID, isSharing := h.hub.GetScreenSharingUser(client.MeetingID); isSharing {
+	if sharingUserID, isSharing := h.hub.GetScreenSharingUser(meetingID); isSharing {
 		// Get the sharing user's client to get username
-		sharingClient := h.hub.GetClient(client.MeetingID, sharingUserID)
+		sharingClient := h.hub.GetClient(meetingID, sharingUserID)
 		if sharingClient != nil {
 			screenShareMsg := &Message{
 				Type:      MessageTypeScreenShareStarted,
 				From:      sharingUserID,
-				To:        requestUserID,
-				MeetingID: client.MeetingID,
+				To:        userID,
+				MeetingID: meetingID,
 				Data: &ScreenShareInfo{
 					UserID:    sharingUserID,
 					Username:  sharingClient.Username,
@@ -464,15 +613,19 @@ func (h *Handler) handleApproveJoinRequest(client *Client, msg *Message) {
 				},
 			}
 			h.hub.SendMessage(screenShareMsg)
-			log.Printf("WebSocket: Sent screen share state to newly joined user %s", requestUserID)
+			log.Printf("WebSocket: Sent screen share state to newly joined user %s", userID)
 		}
 	}
-
-	log.Printf("WebSocket: Host %s approved join request from %s (%s)", client.UserID, joinRequest.Username, requestUserID)
+	return nil
 }
 
 // handleRejectJoinRequest handles rejection from host
 func (h *Handler) handleRejectJoinRequest(client *Client, msg *Message) {
+	if !h.canAdmit(client) {
+		h.sendError(client, "Only the host or moderators can reject users")
+		return
+	}
+
 	// Parse rejection data
 	data, ok := msg.Data.(map[string]interface{})
 	if !ok {
@@ -516,10 +669,36 @@ func (h *Handler) handleRejectJoinRequest(client *Client, msg *Message) {
 		},
 	}
 	h.hub.SendMessage(rejectionMsg)
+	h.recordAudit(client, models.AuditActionJoinRejected, &requestUserID, map[string]interface{}{
+		"username": joinRequest.Username,
+	})
 
 	log.Printf("WebSocket: Host %s rejected join request from %s (%s)", client.UserID, joinRequest.Username, requestUserID)
 }
 
+// handleMediaStateChanged validates a media state change, relays it to the
+// other participants and records it on the participant
+func (h *Handler) handleMediaStateChanged(client *Client, msg *Message) {
+	var state MediaState
+	if err := decodeData(msg.Data, &state); err != nil || state.IsEmpty() {
+		log.Printf("WebSocket: Invalid media state data from %s: %v", client.UserID, err)
+		h.sendError(client, "Invalid media state data")
+		return
+	}
+
+	// Screen sharing is driven by the dedicated screen share messages
+	state.IsSharing = nil
+	if state.IsEmpty() {
+		return
+	}
+
+	log.Printf("WebSocket: User %s changed media state in meeting %s", client.UserID, client.MeetingID)
+
+	msg.Data = state
+	h.hub.SendMessage(msg)
+	h.mediaState.Record(client.MeetingID, client.UserID, state)
+}
+
 // handleScreenShareStarted handles screen share started message
 func (h *Handler) handleScreenShareStarted(client *Client, msg *Message) {
 	log.Printf("WebSocket: User %s started screen sharing in meeting %s", client.UserID, client.MeetingID)
@@ -559,6 +738,9 @@ func (h *Handler) handleScreenShareStarted(client *Client, msg *Message) {
 		Data:      screenShareInfo,
 	}
 	h.hub.SendMessage(broadcastMsg)
+	h.mediaState.Record(client.MeetingID, client.UserID, MediaState{IsSharing: boolPtr(true)})
+	h.postSystemMessage(client, models.SystemMessageScreenShareStarted, nil)
+	h.recordAudit(client, models.AuditActionScreenShareStarted, nil, nil)
 
 	log.Printf("WebSocket: Screen sharing started broadcast sent for user %s", client.UserID)
 }
@@ -587,10 +769,35 @@ func (h *Handler) handleScreenShareStopped(client *Client, msg *Message) {
 		},
 	}
 	h.hub.SendMessage(broadcastMsg)
+	h.mediaState.Record(client.MeetingID, client.UserID, MediaState{IsSharing: boolPtr(false)})
+	h.postSystemMessage(client, models.SystemMessageScreenShareStopped, nil)
+	h.recordAudit(client, models.AuditActionScreenShareStopped, nil, nil)
 
 	log.Printf("WebSocket: Screen sharing stopped broadcast sent for user %s", client.UserID)
 }
 
+// joinAccessErrorMessage describes a failed passcode, invite or capacity
+// check to the joining user
+func joinAccessErrorMessage(err error) string {
+	var lockedErr *service.PasscodeLockedError
+	switch {
+	case errors.As(err, &lockedErr):
+		return "Too many wrong passcodes, try again later"
+	case err == service.ErrPasscodeRequired:
+		return "Passcode required"
+	case err == service.ErrInvalidPasscode:
+		return "Invalid passcode"
+	case err == service.ErrInvalidInvite:
+		return "Invalid or expired invite link"
+	case err == service.ErrMeetingEnded:
+		return "Meeting has ended"
+	case err == service.ErrMeetingFull:
+		return "Meeting is full"
+	default:
+		return "Failed to verify meeting access"
+	}
+}
+
 // sendError sends an error message to a client
 func (h *Handler) sendError(client *Client, message string) {
 	errorMsg := &Message{
@@ -603,3 +810,28 @@ func (h *Handler) sendError(client *Client, message string) {
 		log.Printf("WebSocket: Failed to send error to client %s", client.UserID)
 	}
 }
+
+// recordAudit adds an action by the client's user in its meeting to the
+// audit log
+func (h *Handler) recordAudit(client *Client, action models.AuditAction, targetUserID *uuid.UUID, payload map[string]interface{}) {
+	actorID, meetingID := client.UserID, client.MeetingID
+	h.auditService.Record(&models.AuditEvent{
+		Action:       action,
+		ActorID:      &actorID,
+		TargetUserID: targetUserID,
+		MeetingID:    &meetingID,
+		Payload:      payload,
+	}, client.info)
+}
+
+// postSystemMessage records an action by the client's user in the meeting chat
+func (h *Handler) postSystemMessage(client *Client, key models.SystemMessageKey, params map[string]string) {
+	h.postSystemMessageFor(client.MeetingID, client.UserID, key, params)
+}
+
+// postSystemMessageFor records an action by a user in the meeting chat
+func (h *Handler) postSystemMessageFor(meetingID, userID uuid.UUID, key models.SystemMessageKey, params map[string]string) {
+	if _, err := h.messageService.SendSystemMessage(meetingID, userID, key, params); err != nil {
+		log.Printf("WebSocket: Failed to post system message %s to meeting %s: %v", key, meetingID, err)
+	}
+}
@@ -5,8 +5,16 @@ import (
 	"sync"
 
 	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/service"
 )
 
+// CloseMeetingEnded is the WebSocket close code sent when the meeting ends
+const CloseMeetingEnded = 4000
+
+// CloseSessionRevoked is the WebSocket close code sent when the user's
+// sessions are revoked, e.g. because an admin suspended the account
+const CloseSessionRevoked = 4001
+
 // Client represents a WebSocket client
 type Client struct {
 	ID        uuid.UUID
@@ -15,6 +23,28 @@ type Client struct {
 	MeetingID uuid.UUID
 	Send      chan []byte
 	Hub       *Hub
+
+	// IsGuest is set for anonymous guests, who always wait for admission
+	IsGuest bool
+
+	// info is where the connection came from, for the audit log
+	info service.ClientInfo
+
+	// closed is signalled to make the write pump send a close frame with
+	// closeCode and closeText and drop the connection
+	closed    chan struct{}
+	closeOnce sync.Once
+	closeCode int
+	closeText string
+}
+
+// Close asks the client's connection to close with the given code
+func (c *Client) Close(code int, text string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeText = text
+		close(c.closed)
+	})
 }
 
 // Hub maintains the set of active WebSocket clients
@@ -457,6 +487,71 @@ func (h *Hub) GetClient(meetingID uuid.UUID, userID uuid.UUID) *Client {
 	return nil
 }
 
+// CloseMeeting disconnects every client of a meeting, approved or waiting,
+// with CloseMeetingEnded and forgets the meeting's pending join requests and
+// screen sharing state
+func (h *Hub) CloseMeeting(meetingID uuid.UUID) {
+	h.mu.Lock()
+	var clients []*Client
+	for _, c := range h.clients[meetingID] {
+		clients = append(clients, c)
+	}
+	for _, c := range h.pendingClients[meetingID] {
+		clients = append(clients, c)
+	}
+	delete(h.clients, meetingID)
+	delete(h.pendingClients, meetingID)
+	delete(h.pendingJoinRequests, meetingID)
+	delete(h.screenSharingUsers, meetingID)
+	h.mu.Unlock()
+
+	for _, c := range clients {
+		c.Close(CloseMeetingEnded, "meeting ended")
+	}
+
+	log.Printf("WebSocket: Closed %d connections of ended meeting %s", len(clients), meetingID)
+}
+
+// DisconnectUser closes every connection of a user, approved or waiting, with
+// CloseSessionRevoked. The usual cleanup runs as each connection goes away.
+func (h *Hub) DisconnectUser(userID uuid.UUID) {
+	h.mu.RLock()
+	var clients []*Client
+	for _, byMeeting := range []map[uuid.UUID]map[uuid.UUID]*Client{h.clients, h.pendingClients} {
+		for _, meetingClients := range byMeeting {
+			if c, ok := meetingClients[userID]; ok {
+				clients = append(clients, c)
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, c := range clients {
+		c.Close(CloseSessionRevoked, "session revoked")
+	}
+
+	if len(clients) > 0 {
+		log.Printf("WebSocket: Closed %d connections of user %s", len(clients), userID)
+	}
+}
+
+// ConnectedUsers returns the users with an open connection per meeting,
+// including those still waiting for approval
+func (h *Hub) ConnectedUsers() map[uuid.UUID][]uuid.UUID {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	users := make(map[uuid.UUID][]uuid.UUID)
+	for _, byMeeting := range []map[uuid.UUID]map[uuid.UUID]*Client{h.clients, h.pendingClients} {
+		for meetingID, clients := range byMeeting {
+			for userID := range clients {
+				users[meetingID] = append(users[meetingID], userID)
+			}
+		}
+	}
+	return users
+}
+
 // StartScreenShare starts screen sharing for a user in a meeting
 func (h *Hub) StartScreenShare(meetingID uuid.UUID, userID uuid.UUID) error {
 	h.mu.Lock()
@@ -0,0 +1,121 @@
+package websocket
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/repository"
+	"github.com/meet-app/backend/internal/service"
+	"github.com/meet-app/backend/internal/sse"
+)
+
+// mediaStateDebounce is how long to wait for further changes before a
+// participant's media state is written to the database
+const mediaStateDebounce = 500 * time.Millisecond
+
+type mediaStateKey struct {
+	MeetingID uuid.UUID
+	UserID    uuid.UUID
+}
+
+type pendingMediaState struct {
+	state MediaState
+	timer *time.Timer
+}
+
+// mediaStateRecorder persists media state changes from signaling to the
+// participant record, coalescing rapid toggles into a single write
+type mediaStateRecorder struct {
+	meetingService  service.MeetingService
+	participantRepo repository.ParticipantRepository
+
+	pending map[mediaStateKey]*pendingMediaState
+	mu      sync.Mutex
+}
+
+func newMediaStateRecorder(
+	meetingService service.MeetingService,
+	participantRepo repository.ParticipantRepository,
+) *mediaStateRecorder {
+	return &mediaStateRecorder{
+		meetingService:  meetingService,
+		participantRepo: participantRepo,
+		pending:         make(map[mediaStateKey]*pendingMediaState),
+	}
+}
+
+// Record queues a change and (re)starts the debounce timer for the participant
+func (r *mediaStateRecorder) Record(meetingID, userID uuid.UUID, change MediaState) {
+	key := mediaStateKey{MeetingID: meetingID, UserID: userID}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.pending[key]
+	if !ok {
+		p = &pendingMediaState{}
+		r.pending[key] = p
+	}
+	p.state.Merge(change)
+
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	p.timer = time.AfterFunc(mediaStateDebounce, func() {
+		r.Flush(meetingID, userID)
+	})
+}
+
+// Flush writes any pending change for the participant immediately
+func (r *mediaStateRecorder) Flush(meetingID, userID uuid.UUID) {
+	key := mediaStateKey{MeetingID: meetingID, UserID: userID}
+
+	r.mu.Lock()
+	p, ok := r.pending[key]
+	if ok {
+		if p.timer != nil {
+			p.timer.Stop()
+		}
+		delete(r.pending, key)
+	}
+	r.mu.Unlock()
+
+	if !ok || p.state.IsEmpty() {
+		return
+	}
+
+	r.persist(meetingID, userID, p.state)
+}
+
+// persist applies the change to the participant and mirrors it over SSE
+func (r *mediaStateRecorder) persist(meetingID, userID uuid.UUID, change MediaState) {
+	participant, err := r.participantRepo.FindByUserAndMeeting(userID, meetingID)
+	if err != nil {
+		log.Printf("WebSocket: Cannot record media state for user %s in meeting %s: %v", userID, meetingID, err)
+		return
+	}
+
+	isMuted, isVideoOn, isSharing := participant.IsMuted, participant.IsVideoOn, participant.IsSharing
+	if change.IsMuted != nil {
+		isMuted = *change.IsMuted
+	}
+	if change.IsVideoOn != nil {
+		isVideoOn = *change.IsVideoOn
+	}
+	if change.IsSharing != nil {
+		isSharing = *change.IsSharing
+	}
+
+	if err := r.meetingService.UpdateParticipantMediaStatus(participant.ID, isMuted, isVideoOn, isSharing); err != nil {
+		log.Printf("WebSocket: Failed to save media state for user %s: %v", userID, err)
+		return
+	}
+
+	participant.IsMuted, participant.IsVideoOn, participant.IsSharing = isMuted, isVideoOn, isSharing
+	sse.GetHub().BroadcastToMeeting(meetingID, sse.Event{
+		Type: sse.EventParticipantUpdated,
+		Data: participant.ToResponse(),
+	})
+}
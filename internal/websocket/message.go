@@ -86,3 +86,29 @@ type ScreenShareInfo struct {
 	Username  string    `json:"username"`
 	Timestamp int64     `json:"timestamp"`
 }
+
+// MediaState represents a participant's media state. Fields left nil are
+// unchanged, so clients may send only what changed.
+type MediaState struct {
+	IsMuted   *bool `json:"is_muted,omitempty"`
+	IsVideoOn *bool `json:"is_video_on,omitempty"`
+	IsSharing *bool `json:"is_sharing,omitempty"`
+}
+
+// IsEmpty reports whether the state carries no changes
+func (m MediaState) IsEmpty() bool {
+	return m.IsMuted == nil && m.IsVideoOn == nil && m.IsSharing == nil
+}
+
+// Merge overlays the non-nil fields of other onto m
+func (m *MediaState) Merge(other MediaState) {
+	if other.IsMuted != nil {
+		m.IsMuted = other.IsMuted
+	}
+	if other.IsVideoOn != nil {
+		m.IsVideoOn = other.IsVideoOn
+	}
+	if other.IsSharing != nil {
+		m.IsSharing = other.IsSharing
+	}
+}
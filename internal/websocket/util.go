@@ -14,3 +14,17 @@ func mustMarshal(v interface{}) []byte {
 	}
 	return data
 }
+
+// decodeData converts a generic message payload into a typed struct
+func decodeData(data interface{}, v interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// boolPtr returns a pointer to b
+func boolPtr(b bool) *bool {
+	return &b
+}
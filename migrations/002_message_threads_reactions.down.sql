@@ -0,0 +1,7 @@
+-- Drop message reactions table
+DROP TABLE IF EXISTS message_reactions;
+
+-- Drop thread and mention columns
+DROP INDEX IF EXISTS idx_messages_parent_id;
+ALTER TABLE messages DROP COLUMN IF EXISTS mentions;
+ALTER TABLE messages DROP COLUMN IF EXISTS parent_id;
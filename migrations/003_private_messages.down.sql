@@ -0,0 +1,15 @@
+-- Restore previous meeting settings default
+ALTER TABLE meetings ALTER COLUMN settings SET DEFAULT '{
+    "allow_chat": true,
+    "allow_screen_share": true,
+    "mute_on_join": false,
+    "video_on_join": true,
+    "waiting_room_enabled": false,
+    "recording_enabled": false
+}'::jsonb;
+
+UPDATE meetings SET settings = settings - 'allow_private_chat';
+
+-- Drop private message column
+DROP INDEX IF EXISTS idx_messages_recipient_id;
+ALTER TABLE messages DROP COLUMN IF EXISTS recipient_id;
@@ -0,0 +1,18 @@
+-- Private direct messages between participants
+ALTER TABLE messages ADD COLUMN recipient_id UUID REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_messages_recipient_id ON messages(recipient_id);
+
+-- Enable private chat for existing meetings and by default
+UPDATE meetings SET settings = settings || '{"allow_private_chat": true}'::jsonb
+    WHERE NOT settings ? 'allow_private_chat';
+
+ALTER TABLE meetings ALTER COLUMN settings SET DEFAULT '{
+    "allow_chat": true,
+    "allow_screen_share": true,
+    "mute_on_join": false,
+    "video_on_join": true,
+    "waiting_room_enabled": false,
+    "recording_enabled": false,
+    "allow_private_chat": true
+}'::jsonb;
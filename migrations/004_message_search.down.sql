@@ -0,0 +1,2 @@
+-- Drop full-text search index
+DROP INDEX IF EXISTS idx_messages_content_fts;
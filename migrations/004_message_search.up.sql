@@ -0,0 +1,4 @@
+-- Full-text search over chat messages.
+-- The expression must match the one used by MessageRepository.Search.
+CREATE INDEX idx_messages_content_fts ON messages
+    USING GIN (to_tsvector('english', content));
@@ -0,0 +1,4 @@
+-- Drop moderation flags
+DROP INDEX IF EXISTS idx_messages_is_flagged;
+ALTER TABLE messages DROP COLUMN IF EXISTS flag_reason;
+ALTER TABLE messages DROP COLUMN IF EXISTS is_flagged;
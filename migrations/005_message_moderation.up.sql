@@ -0,0 +1,5 @@
+-- Moderation flags on messages
+ALTER TABLE messages ADD COLUMN is_flagged BOOLEAN DEFAULT FALSE;
+ALTER TABLE messages ADD COLUMN flag_reason TEXT;
+
+CREATE INDEX idx_messages_is_flagged ON messages(is_flagged);
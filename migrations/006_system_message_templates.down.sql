@@ -0,0 +1,3 @@
+-- Drop system message template columns
+ALTER TABLE messages DROP COLUMN IF EXISTS template_params;
+ALTER TABLE messages DROP COLUMN IF EXISTS template_key;
@@ -0,0 +1,3 @@
+-- Template key and parameters for localisable system messages
+ALTER TABLE messages ADD COLUMN template_key VARCHAR(64);
+ALTER TABLE messages ADD COLUMN template_params JSONB;
@@ -0,0 +1,2 @@
+-- Drop participant attendance sessions
+DROP TABLE IF EXISTS participant_sessions;
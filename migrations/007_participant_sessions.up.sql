@@ -0,0 +1,20 @@
+-- Attendance sessions: one row per join/leave interval of a participant
+CREATE TABLE participant_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    participant_id UUID NOT NULL REFERENCES participants(id) ON DELETE CASCADE,
+    meeting_id UUID NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    joined_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    left_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_participant_sessions_participant_id ON participant_sessions(participant_id);
+CREATE INDEX idx_participant_sessions_meeting_id ON participant_sessions(meeting_id);
+CREATE INDEX idx_participant_sessions_user_id ON participant_sessions(user_id);
+
+-- Backfill the single known interval of existing participants
+INSERT INTO participant_sessions (participant_id, meeting_id, user_id, joined_at, left_at)
+SELECT id, meeting_id, user_id, joined_at, left_at
+FROM participants
+WHERE deleted_at IS NULL;
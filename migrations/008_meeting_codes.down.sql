@@ -0,0 +1,3 @@
+-- Drop normalized meeting code index
+DROP INDEX IF EXISTS idx_meetings_code_normalized;
+-- The code column is left at VARCHAR(36) since existing codes may not fit VARCHAR(10)
@@ -0,0 +1,5 @@
+-- Human-friendly codes (abc-defg-hij) are longer than the original column
+ALTER TABLE meetings ALTER COLUMN code TYPE VARCHAR(36);
+
+-- Case- and dash-insensitive code lookup
+CREATE INDEX idx_meetings_code_normalized ON meetings ((REPLACE(LOWER(code), '-', '')));
@@ -0,0 +1,3 @@
+-- Drop meeting invites and passcodes
+DROP TABLE IF EXISTS meeting_invites;
+ALTER TABLE meetings DROP COLUMN IF EXISTS passcode_hash;
@@ -0,0 +1,17 @@
+-- Optional meeting passcode (bcrypt hash; empty means no passcode)
+ALTER TABLE meetings ADD COLUMN passcode_hash VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Host-issued invite links; the link carries a signed token for the row
+CREATE TABLE meeting_invites (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    meeting_id UUID NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    max_uses INTEGER NOT NULL DEFAULT 0,
+    uses INTEGER NOT NULL DEFAULT 0,
+    bypass_waiting_room BOOLEAN NOT NULL DEFAULT FALSE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_meeting_invites_meeting_id ON meeting_invites(meeting_id);
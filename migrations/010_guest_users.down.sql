@@ -0,0 +1,3 @@
+-- Remove guest users and the guest flag
+DELETE FROM users WHERE is_guest;
+ALTER TABLE users DROP COLUMN IF EXISTS is_guest;
@@ -0,0 +1,2 @@
+-- Anonymous meeting guests get a credential-less user row
+ALTER TABLE users ADD COLUMN is_guest BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+-- Remove the login audit trail
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,4 @@
+-- Remove single sign-on identities and user roles
+ALTER TABLE login_attempts DROP COLUMN IF EXISTS method;
+DROP TABLE IF EXISTS user_identities;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
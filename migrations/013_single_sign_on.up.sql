@@ -0,0 +1,22 @@
+-- Deployment-wide user roles, mapped from identity provider groups on SSO login
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'member';
+
+-- Accounts at external identity providers linked to users
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_user_identities_issuer_subject ON user_identities(issuer, subject);
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+CREATE TRIGGER update_user_identities_updated_at BEFORE UPDATE ON user_identities
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- How each login was attempted
+ALTER TABLE login_attempts ADD COLUMN method VARCHAR(20) NOT NULL DEFAULT 'password';
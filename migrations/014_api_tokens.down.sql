@@ -0,0 +1,5 @@
+-- Remove personal API tokens and service accounts
+DROP TABLE IF EXISTS api_tokens;
+DELETE FROM users WHERE is_service_account;
+ALTER TABLE users DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE users DROP COLUMN IF EXISTS is_service_account;
@@ -0,0 +1,6 @@
+-- Remove organizations
+DROP INDEX IF EXISTS idx_meetings_organization_id;
+ALTER TABLE meetings DROP COLUMN IF EXISTS visibility;
+ALTER TABLE meetings DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,3 @@
+-- Admins can suspend users, which blocks logins and API tokens
+ALTER TABLE users ADD COLUMN suspended_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN suspension_reason VARCHAR(500) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Remove the audit log
+DROP TRIGGER IF EXISTS reject_audit_events_update ON audit_events;
+DROP FUNCTION IF EXISTS reject_audit_event_update();
+DROP TABLE IF EXISTS audit_events;
@@ -0,0 +1,30 @@
+-- Append-only log of security- and moderation-relevant actions. The ids have
+-- no foreign keys so events outlive the users and meetings they mention.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    action VARCHAR(50) NOT NULL,
+    actor_id UUID,
+    target_user_id UUID,
+    meeting_id UUID,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    payload JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_events_meeting_id_created_at ON audit_events(meeting_id, created_at);
+CREATE INDEX idx_audit_events_actor_id ON audit_events(actor_id);
+CREATE INDEX idx_audit_events_target_user_id ON audit_events(target_user_id);
+CREATE INDEX idx_audit_events_action ON audit_events(action);
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at);
+
+-- Events are never changed; old ones are only deleted by retention
+CREATE OR REPLACE FUNCTION reject_audit_event_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit events are append-only';
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER reject_audit_events_update BEFORE UPDATE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION reject_audit_event_update();
@@ -0,0 +1,3 @@
+-- Restore the non-unique normalized meeting code index
+DROP INDEX IF EXISTS idx_meetings_code_normalized;
+CREATE INDEX idx_meetings_code_normalized ON meetings ((REPLACE(LOWER(code), '-', '')));
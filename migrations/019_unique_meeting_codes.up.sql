@@ -0,0 +1,4 @@
+-- Codes must stay unique ignoring case and dashes; the index also settles
+-- concurrent inserts of the same code
+DROP INDEX IF EXISTS idx_meetings_code_normalized;
+CREATE UNIQUE INDEX idx_meetings_code_normalized ON meetings ((REPLACE(LOWER(code), '-', '')));
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InviteClaims identify the meeting invite a signed invite token stands for
+type InviteClaims struct {
+	InviteID  uuid.UUID `json:"iid"`
+	MeetingID uuid.UUID `json:"mid"`
+	ExpiresAt int64     `json:"exp"`
+}
+
+// SignInvite encodes the claims and signs them with HMAC-SHA256
+func SignInvite(claims InviteClaims, secret string) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + inviteSignature(encoded, secret), nil
+}
+
+// ParseInvite verifies an invite token and returns its claims
+func ParseInvite(token, secret string) (*InviteClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	expected := inviteSignature(encoded, secret)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims InviteClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+
+	return &claims, nil
+}
+
+func inviteSignature(encoded, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("invite." + encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
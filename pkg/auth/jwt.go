@@ -18,6 +18,9 @@ type Claims struct {
 	UserID   uuid.UUID `json:"user_id"`
 	Email    string    `json:"email"`
 	Username string    `json:"username"`
+	// Guest tokens only grant access to the signaling and events of MeetingID
+	Guest     bool       `json:"guest,omitempty"`
+	MeetingID *uuid.UUID `json:"meeting_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -28,15 +31,15 @@ type TokenPair struct {
 }
 
 // GenerateTokenPair generates both access and refresh tokens
-func GenerateTokenPair(userID uuid.UUID, email, username string, cfg *config.JWTConfig) (*TokenPair, error) {
+func GenerateTokenPair(userID uuid.UUID, email, username string, keys *Keyring, cfg *config.JWTConfig) (*TokenPair, error) {
 	// Generate access token
-	accessToken, expiresAt, err := GenerateToken(userID, email, username, cfg.Secret, cfg.ExpiryHours)
+	accessToken, expiresAt, err := GenerateToken(userID, email, username, keys, cfg.ExpiryHours)
 	if err != nil {
 		return nil, err
 	}
 
 	// Generate refresh token (with longer expiry)
-	refreshToken, _, err := GenerateToken(userID, email, username, cfg.Secret, cfg.RefreshHours)
+	refreshToken, _, err := GenerateToken(userID, email, username, keys, cfg.RefreshHours)
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +52,7 @@ func GenerateTokenPair(userID uuid.UUID, email, username string, cfg *config.JWT
 }
 
 // GenerateToken generates a JWT token for a user
-func GenerateToken(userID uuid.UUID, email, username, secret string, expiryHours int) (string, time.Time, error) {
+func GenerateToken(userID uuid.UUID, email, username string, keys *Keyring, expiryHours int) (string, time.Time, error) {
 	expiresAt := time.Now().Add(time.Duration(expiryHours) * time.Hour)
 
 	claims := Claims{
@@ -64,8 +67,7 @@ func GenerateToken(userID uuid.UUID, email, username, secret string, expiryHours
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(secret))
+	signedToken, err := keys.Sign(claims)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -73,15 +75,35 @@ func GenerateToken(userID uuid.UUID, email, username, secret string, expiryHours
 	return signedToken, expiresAt, nil
 }
 
-// ValidateToken validates a JWT token and returns the claims
-func ValidateToken(tokenString, secret string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verify signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return []byte(secret), nil
-	})
+// GenerateGuestToken generates a short-lived JWT that lets a guest into one meeting
+func GenerateGuestToken(userID uuid.UUID, username string, meetingID uuid.UUID, keys *Keyring, expiry time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(expiry)
+
+	claims := Claims{
+		UserID:    userID,
+		Username:  username,
+		Guest:     true,
+		MeetingID: &meetingID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    "meet-app",
+		},
+	}
+
+	signedToken, err := keys.Sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signedToken, expiresAt, nil
+}
+
+// ValidateToken validates a JWT token and returns the claims. The key is
+// selected by the token's kid header.
+func ValidateToken(tokenString string, keys *Keyring) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keys.keyFunc)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -99,11 +121,16 @@ func ValidateToken(tokenString, secret string) (*Claims, error) {
 }
 
 // RefreshAccessToken generates a new access token from a valid refresh token
-func RefreshAccessToken(refreshToken string, cfg *config.JWTConfig) (*TokenPair, error) {
-	claims, err := ValidateToken(refreshToken, cfg.Secret)
+func RefreshAccessToken(refreshToken string, keys *Keyring, cfg *config.JWTConfig) (*TokenPair, error) {
+	claims, err := ValidateToken(refreshToken, keys)
 	if err != nil {
 		return nil, err
 	}
 
-	return GenerateTokenPair(claims.UserID, claims.Email, claims.Username, cfg)
+	// Guest tokens cannot be exchanged for account tokens
+	if claims.Guest {
+		return nil, ErrInvalidToken
+	}
+
+	return GenerateTokenPair(claims.UserID, claims.Email, claims.Username, keys, cfg)
 }
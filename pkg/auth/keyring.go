@@ -0,0 +1,445 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/meet-app/backend/internal/config"
+)
+
+// Supported token signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var ErrUnsupportedAlgorithm = errors.New("unsupported JWT signing algorithm")
+
+const (
+	rsaKeyBits = 2048
+
+	// A kid missing from the keyring reloads the key directory at most this
+	// often, to pick up keys created by other instances
+	keyReloadInterval = time.Minute
+
+	// How often RunRotation checks whether the active key is due
+	rotationCheckInterval = time.Hour
+
+	// New keys are published in the JWKS this long before they sign tokens,
+	// so verifiers that cache the JWKS know them in time
+	keyPublishDelay = 10 * time.Minute
+
+	keyFileSuffix     = ".pem"
+	keyCreatedHeader  = "Created"
+	keyFilePermission = 0o600
+)
+
+// signingKey is one key of the keyring
+type signingKey struct {
+	id        string
+	private   interface{} // []byte for HMAC, otherwise a crypto.Signer
+	public    interface{}
+	createdAt time.Time
+}
+
+// Keyring signs tokens with its active key and validates them with any key
+// that has not been retired for longer than tokens live. Asymmetric keys are
+// stored as PEM files in a directory that instances can share, and are
+// identified in tokens by their kid header.
+type Keyring struct {
+	method      jwt.SigningMethod
+	secret      []byte // HS256 only
+	dir         string
+	rotateEvery time.Duration
+	retainFor   time.Duration
+
+	mu         sync.RWMutex
+	keys       map[string]*signingKey
+	lastReload time.Time
+}
+
+// NewKeyring loads the keys configured in cfg. With HS256 the keyring holds
+// only the shared secret; otherwise it loads the key directory and creates
+// a key if none is active.
+func NewKeyring(cfg *config.JWTConfig) (*Keyring, error) {
+	k := &Keyring{
+		dir:         cfg.KeyDir,
+		rotateEvery: time.Duration(cfg.KeyRotationDays) * 24 * time.Hour,
+		// A retired key must verify every token it signed until that expires
+		retainFor: time.Duration(max(cfg.ExpiryHours, cfg.RefreshHours)) * time.Hour,
+		keys:      make(map[string]*signingKey),
+	}
+
+	switch cfg.Algorithm {
+	case AlgorithmHS256:
+		k.method = jwt.SigningMethodHS256
+		k.secret = []byte(cfg.Secret)
+		return k, nil
+	case AlgorithmRS256:
+		k.method = jwt.SigningMethodRS256
+	case AlgorithmEdDSA:
+		k.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, cfg.Algorithm)
+	}
+
+	if err := os.MkdirAll(k.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating key directory: %w", err)
+	}
+	if err := k.Rotate(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Algorithm returns the signing algorithm
+func (k *Keyring) Algorithm() string {
+	return k.method.Alg()
+}
+
+// Sign signs the claims with the active key
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.isSymmetric() {
+		return token.SignedString(k.secret)
+	}
+
+	k.mu.RLock()
+	active := k.activeKey()
+	k.mu.RUnlock()
+	if active == nil {
+		return "", errors.New("no active signing key")
+	}
+
+	token.Header["kid"] = active.id
+	return token.SignedString(active.private)
+}
+
+// keyFunc returns the key that verifies the token, selected by its kid
+func (k *Keyring) keyFunc(token *jwt.Token) (interface{}, error) {
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, ErrInvalidToken
+	}
+	if k.isSymmetric() {
+		return k.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrInvalidToken
+	}
+
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	stale := time.Since(k.lastReload) >= keyReloadInterval
+	k.mu.RUnlock()
+	if ok {
+		return key.public, nil
+	}
+
+	// Another instance may have rotated
+	if stale {
+		k.mu.Lock()
+		err := k.load()
+		key, ok = k.keys[kid]
+		k.mu.Unlock()
+		if err != nil {
+			log.Printf("Auth: failed to reload signing keys: %v", err)
+		}
+		if ok {
+			return key.public, nil
+		}
+	}
+	return nil, ErrInvalidToken
+}
+
+// Rotate reloads the key directory and creates a new key if there is none or
+// the newest one is older than the rotation period. Retired keys that can no
+// longer have valid tokens are deleted.
+func (k *Keyring) Rotate() error {
+	if k.isSymmetric() {
+		return nil
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := k.load(); err != nil {
+		return err
+	}
+
+	keys := k.sortedKeys()
+	if len(keys) == 0 || (k.rotateEvery > 0 && time.Since(keys[0].createdAt) >= k.rotateEvery) {
+		key, err := k.generate()
+		if err != nil {
+			return err
+		}
+		k.keys[key.id] = key
+		log.Printf("Auth: created signing key %s", key.id)
+	}
+
+	k.prune()
+	return nil
+}
+
+// RunRotation rotates the active key on schedule until ctx is cancelled
+func (k *Keyring) RunRotation(ctx context.Context) {
+	if k.isSymmetric() || k.rotateEvery <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(rotationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.Rotate(); err != nil {
+				log.Printf("Auth: signing key rotation failed: %v", err)
+			}
+		}
+	}
+}
+
+// JWKS returns the public keys that verify tokens, including a new key
+// before it becomes active. Shared HS256 secrets are never published, so the
+// set is empty for HS256.
+func (k *Keyring) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+	if k.isSymmetric() {
+		return set
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, key := range k.sortedKeys() {
+		jwk := JSONWebKey{Kid: key.id, Use: "sig", Alg: k.method.Alg()}
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// JSONWebKeySet is a JWKS document (RFC 7517)
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JSONWebKey is a public key in a JWKS document
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+func (k *Keyring) isSymmetric() bool {
+	return k.method == jwt.SigningMethodHS256
+}
+
+// activeKey returns the newest published key, or the newest key if none has
+// been published long enough, e.g. on first start. The caller holds the lock.
+func (k *Keyring) activeKey() *signingKey {
+	var newest, published *signingKey
+	for _, key := range k.keys {
+		if newest == nil || key.createdAt.After(newest.createdAt) {
+			newest = key
+		}
+		if time.Since(key.createdAt) >= keyPublishDelay &&
+			(published == nil || key.createdAt.After(published.createdAt)) {
+			published = key
+		}
+	}
+	if published != nil {
+		return published
+	}
+	return newest
+}
+
+// load reads every key file for the keyring's algorithm. The caller holds
+// the write lock.
+func (k *Keyring) load() error {
+	entries, err := os.ReadDir(k.dir)
+	if err != nil {
+		return fmt.Errorf("reading key directory: %w", err)
+	}
+
+	keys := make(map[string]*signingKey, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), keyFileSuffix) {
+			continue
+		}
+
+		key, err := k.readKeyFile(filepath.Join(k.dir, entry.Name()))
+		if err != nil {
+			log.Printf("Auth: skipping key file %s: %v", entry.Name(), err)
+			continue
+		}
+		if key == nil {
+			continue
+		}
+
+		keys[key.id] = key
+	}
+
+	k.keys = keys
+	k.lastReload = time.Now()
+	return nil
+}
+
+// readKeyFile parses a key file, returning nil for keys of another algorithm
+func (k *Keyring) readKeyFile(path string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data")
+	}
+	createdAt, err := time.Parse(time.RFC3339, block.Headers[keyCreatedHeader])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s header: %w", keyCreatedHeader, err)
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{
+		id:        strings.TrimSuffix(filepath.Base(path), keyFileSuffix),
+		private:   private,
+		createdAt: createdAt,
+	}
+	switch priv := private.(type) {
+	case *rsa.PrivateKey:
+		if k.method != jwt.SigningMethodRS256 {
+			return nil, nil
+		}
+		key.public = &priv.PublicKey
+	case ed25519.PrivateKey:
+		if k.method != jwt.SigningMethodEdDSA {
+			return nil, nil
+		}
+		key.public = priv.Public()
+	default:
+		return nil, nil
+	}
+	return key, nil
+}
+
+// generate creates a key and writes it to the key directory
+func (k *Keyring) generate() (*signingKey, error) {
+	var private crypto.Signer
+	var err error
+	switch k.method {
+	case jwt.SigningMethodRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case jwt.SigningMethodEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("generating signing key: %w", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, err
+	}
+	key := &signingKey{
+		id:        hex.EncodeToString(idBytes),
+		private:   private,
+		public:    private.Public(),
+		createdAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{
+		Type:    "PRIVATE KEY",
+		Headers: map[string]string{keyCreatedHeader: key.createdAt.Format(time.RFC3339)},
+		Bytes:   der,
+	})
+	path := filepath.Join(k.dir, key.id+keyFileSuffix)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, keyFilePermission)
+	if err != nil {
+		return nil, fmt.Errorf("writing signing key: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		return nil, fmt.Errorf("writing signing key: %w", err)
+	}
+	return key, nil
+}
+
+// prune deletes keys whose successor has been active for longer than tokens
+// live. The caller holds the write lock.
+func (k *Keyring) prune() {
+	keys := k.sortedKeys()
+	for i := 1; i < len(keys); i++ {
+		retired, successor := keys[i], keys[i-1]
+		if time.Since(successor.createdAt) < keyPublishDelay+k.retainFor {
+			continue
+		}
+
+		path := filepath.Join(k.dir, retired.id+keyFileSuffix)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("Auth: failed to delete retired signing key %s: %v", retired.id, err)
+			continue
+		}
+		delete(k.keys, retired.id)
+		log.Printf("Auth: deleted retired signing key %s", retired.id)
+	}
+}
+
+// sortedKeys returns the keys newest first
+func (k *Keyring) sortedKeys() []*signingKey {
+	keys := make([]*signingKey, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].createdAt.After(keys[j].createdAt)
+	})
+	return keys
+}
@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/meet-app/backend/internal/config"
+)
+
+func newTestKeyring(t *testing.T) *Keyring {
+	t.Helper()
+	keys, err := NewKeyring(&config.JWTConfig{
+		Algorithm:       AlgorithmEdDSA,
+		KeyDir:          t.TempDir(),
+		KeyRotationDays: 1,
+		ExpiryHours:     1,
+		RefreshHours:    2,
+	})
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	return keys
+}
+
+// backdate rewrites the key's creation time as if it was created age ago,
+// which the keyring picks up on its next Rotate
+func backdate(t *testing.T, keys *Keyring, kid string, age time.Duration) {
+	t.Helper()
+	path := filepath.Join(keys.dir, kid+keyFileSuffix)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading key %s: %v", kid, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatalf("key %s has no PEM data", kid)
+	}
+	block.Headers[keyCreatedHeader] = time.Now().Add(-age).UTC().Format(time.RFC3339)
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), keyFilePermission); err != nil {
+		t.Fatalf("writing key %s: %v", kid, err)
+	}
+}
+
+func signTestToken(t *testing.T, keys *Keyring) (string, string) {
+	t.Helper()
+	token, _, err := GenerateToken(uuid.New(), "user@example.com", "user", keys, 1)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+	if err != nil {
+		t.Fatalf("parsing token: %v", err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return token, kid
+}
+
+func jwksKids(keys *Keyring) map[string]bool {
+	kids := make(map[string]bool)
+	for _, key := range keys.JWKS().Keys {
+		kids[key.Kid] = true
+	}
+	return kids
+}
+
+func TestKeyringRotation(t *testing.T) {
+	keys := newTestKeyring(t)
+
+	oldToken, oldKid := signTestToken(t, keys)
+	if oldKid == "" {
+		t.Fatal("token has no kid")
+	}
+
+	// Past the rotation period a new key is created, but the old one keeps
+	// signing until the new one has been published long enough
+	backdate(t, keys, oldKid, 25*time.Hour)
+	if err := keys.Rotate(); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if n := len(keys.JWKS().Keys); n != 2 {
+		t.Fatalf("JWKS has %d keys after rotation, want 2", n)
+	}
+	if _, kid := signTestToken(t, keys); kid != oldKid {
+		t.Errorf("unpublished key signed a token")
+	}
+
+	var newKid string
+	for kid := range jwksKids(keys) {
+		if kid != oldKid {
+			newKid = kid
+		}
+	}
+	backdate(t, keys, newKid, keyPublishDelay+time.Minute)
+	if err := keys.Rotate(); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	newToken, kid := signTestToken(t, keys)
+	if kid != newKid {
+		t.Fatalf("token signed with %s, want the new key %s", kid, newKid)
+	}
+
+	// Tokens of both keys verify
+	for _, token := range []string{oldToken, newToken} {
+		if _, err := ValidateToken(token, keys); err != nil {
+			t.Errorf("ValidateToken: %v", err)
+		}
+	}
+}
+
+func TestKeyringPrunedKey(t *testing.T) {
+	keys := newTestKeyring(t)
+	oldToken, oldKid := signTestToken(t, keys)
+
+	backdate(t, keys, oldKid, 25*time.Hour)
+	if err := keys.Rotate(); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	// Once the successor has been active for longer than tokens live, the
+	// retired key is deleted and its tokens no longer verify
+	for kid := range jwksKids(keys) {
+		if kid != oldKid {
+			backdate(t, keys, kid, keyPublishDelay+keys.retainFor+time.Minute)
+		}
+	}
+	if err := keys.Rotate(); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	if jwksKids(keys)[oldKid] {
+		t.Error("pruned key is still published")
+	}
+	if _, err := os.Stat(filepath.Join(keys.dir, oldKid+keyFileSuffix)); !os.IsNotExist(err) {
+		t.Errorf("pruned key file still exists: %v", err)
+	}
+	if _, err := ValidateToken(oldToken, keys); err != ErrInvalidToken {
+		t.Errorf("ValidateToken with a pruned kid = %v, want ErrInvalidToken", err)
+	}
+
+	newToken, _ := signTestToken(t, keys)
+	if _, err := ValidateToken(newToken, keys); err != nil {
+		t.Errorf("ValidateToken: %v", err)
+	}
+}
+
+func TestKeyringUnknownKid(t *testing.T) {
+	keys := newTestKeyring(t)
+	other := newTestKeyring(t)
+
+	token, _ := signTestToken(t, other)
+	if _, err := ValidateToken(token, keys); err != ErrInvalidToken {
+		t.Errorf("ValidateToken with another keyring's kid = %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// GenerateOpaqueToken returns a random URL-safe token and the hash to store
+// in its place
+func GenerateOpaqueToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashOpaqueToken(token), nil
+}
+
+// HashOpaqueToken hashes a token for storage and lookup
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// APITokenPrefix marks personal API tokens so they can be told apart from
+// JWTs and recognised by secret scanners
+const APITokenPrefix = "mat_"
+
+// GenerateAPIToken returns a new personal API token and its hash
+func GenerateAPIToken() (token, hash string, err error) {
+	opaque, _, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	token = APITokenPrefix + opaque
+	return token, HashOpaqueToken(token), nil
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// RevocationStore records, per user, a point in time before which all issued
+// tokens are no longer accepted, e.g. after a password change
+type RevocationStore struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRevocationStore creates a store whose entries live for ttl, which should
+// be at least the lifetime of the longest-lived token
+func NewRevocationStore(client *redis.Client, ttl time.Duration) *RevocationStore {
+	return &RevocationStore{
+		client: client,
+		prefix: "auth:revoked_before:",
+		ttl:    ttl,
+	}
+}
+
+// RevokeBefore revokes the user's tokens issued before t
+func (s *RevocationStore) RevokeBefore(ctx context.Context, userID uuid.UUID, t time.Time) error {
+	return s.client.Set(ctx, s.prefix+userID.String(), t.Unix(), s.ttl).Err()
+}
+
+// IsRevoked reports whether the token with these claims has been revoked
+func (s *RevocationStore) IsRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	value, err := s.client.Get(ctx, s.prefix+claims.UserID.String()).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	revokedBefore, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	if claims.IssuedAt == nil {
+		return true, nil
+	}
+	return claims.IssuedAt.Unix() < revokedBefore, nil
+}
@@ -0,0 +1,63 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer writes each message as an .eml file, for local testing
+type FileMailer struct {
+	dir  string
+	from *mail.Address
+}
+
+// NewFileMailer creates a mailer that writes messages into dir
+func NewFileMailer(dir string, from *mail.Address) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+// Send writes the message to a new file
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	body, err := build(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	boundary, err := randomBoundary()
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405"), boundary[:8])
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return err
+	}
+
+	log.Printf("Mail: wrote %q for %s to %s", msg.Subject, msg.To, path)
+	return nil
+}
+
+// LogMailer prints messages to the log instead of sending them
+type LogMailer struct {
+	from *mail.Address
+}
+
+// NewLogMailer creates a mailer that logs messages
+func NewLogMailer(from *mail.Address) *LogMailer {
+	return &LogMailer{from: from}
+}
+
+// Send logs the plain text version of the message
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("Mail: from %s to %s\nSubject: %s\n\n%s", m.from, msg.To, msg.Subject, msg.Text)
+	return nil
+}
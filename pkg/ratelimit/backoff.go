@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// backoffFailScript counts a failure and, past the free attempts, blocks the
+// key for a delay that doubles with every further failure. The count expires
+// once no failure happened for the window. It returns the delay in
+// milliseconds, or 0 if the key is not blocked.
+var backoffFailScript = redis.NewScript(`
+local failures = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+
+local excess = failures - tonumber(ARGV[1])
+if excess <= 0 then
+	return 0
+end
+
+local delay = math.floor(tonumber(ARGV[2]) * math.pow(2, math.min(excess - 1, 30)))
+delay = math.min(delay, tonumber(ARGV[3]))
+redis.call('SET', KEYS[2], 1, 'PX', delay)
+return delay
+`)
+
+// Backoff delays further attempts on a key exponentially once it has failed
+// more than a number of times, e.g. logins from one IP address
+type Backoff struct {
+	client       *redis.Client
+	prefix       string
+	freeAttempts int
+	base         time.Duration
+	max          time.Duration
+	window       time.Duration
+}
+
+// NewBackoff creates a backoff that allows freeAttempts failures, then blocks
+// for base, 2*base, 4*base... up to max. Failures are forgotten after window
+// without one.
+func NewBackoff(client *redis.Client, prefix string, freeAttempts int, base, max, window time.Duration) *Backoff {
+	return &Backoff{
+		client:       client,
+		prefix:       prefix,
+		freeAttempts: freeAttempts,
+		base:         base,
+		max:          max,
+		window:       window,
+	}
+}
+
+// Wait returns how long the key remains blocked, or 0 if it is not
+func (b *Backoff) Wait(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := b.client.PTTL(ctx, b.blockKey(key)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("backoff check failed: %w", err)
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// Fail records a failed attempt and returns how long the key is now blocked
+func (b *Backoff) Fail(ctx context.Context, key string) (time.Duration, error) {
+	ms, err := backoffFailScript.Run(ctx, b.client,
+		[]string{b.prefix + key, b.blockKey(key)},
+		b.freeAttempts, b.base.Milliseconds(), b.max.Milliseconds(), b.window.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("backoff update failed: %w", err)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// Reset forgets the failures of a key after a successful attempt
+func (b *Backoff) Reset(ctx context.Context, key string) error {
+	return b.client.Del(ctx, b.prefix+key, b.blockKey(key)).Err()
+}
+
+func (b *Backoff) blockKey(key string) string {
+	return b.prefix + key + ":blocked"
+}
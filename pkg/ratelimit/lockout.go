@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// lockoutFailScript counts a failure within the window and, once the limit is
+// reached, locks the key and resets the count. It returns the lock duration in
+// milliseconds, or 0 if the key is not locked.
+var lockoutFailScript = redis.NewScript(`
+local failures = redis.call('INCR', KEYS[1])
+if failures == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+
+if failures >= tonumber(ARGV[1]) then
+	redis.call('SET', KEYS[2], 1, 'PX', ARGV[3])
+	redis.call('DEL', KEYS[1])
+	return tonumber(ARGV[3])
+end
+return 0
+`)
+
+// Lockout blocks a key for a cooldown after too many failed attempts within a
+// window, e.g. wrong passcodes for one user and meeting
+type Lockout struct {
+	client      *redis.Client
+	prefix      string
+	maxAttempts int
+	window      time.Duration
+	lockFor     time.Duration
+}
+
+// NewLockout creates a lockout that locks a key for lockFor once maxAttempts
+// failures happen within window
+func NewLockout(client *redis.Client, prefix string, maxAttempts int, window, lockFor time.Duration) *Lockout {
+	return &Lockout{
+		client:      client,
+		prefix:      prefix,
+		maxAttempts: maxAttempts,
+		window:      window,
+		lockFor:     lockFor,
+	}
+}
+
+// Locked returns how long the key remains locked, or 0 if it is not
+func (l *Lockout) Locked(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := l.client.PTTL(ctx, l.lockKey(key)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("lockout check failed: %w", err)
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// Fail records a failed attempt and returns the lock duration if this
+// failure locked the key
+func (l *Lockout) Fail(ctx context.Context, key string) (time.Duration, error) {
+	ms, err := lockoutFailScript.Run(ctx, l.client,
+		[]string{l.prefix + key, l.lockKey(key)},
+		l.maxAttempts, l.window.Milliseconds(), l.lockFor.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("lockout update failed: %w", err)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// Reset forgets the failures of a key after a successful attempt
+func (l *Lockout) Reset(ctx context.Context, key string) error {
+	return l.client.Del(ctx, l.prefix+key).Err()
+}
+
+func (l *Lockout) lockKey(key string) string {
+	return l.prefix + key + ":locked"
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript refills the bucket based on the Redis server clock (so all
+// replicas agree on time), then tries to take one token. It returns whether the
+// request is allowed and, if not, how many milliseconds until a token is free.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1]) or capacity
+local ts = tonumber(data[2]) or now
+
+tokens = math.min(capacity, tokens + (math.max(0, now - ts) / 1000) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity / rate * 1000) + 1000)
+
+return {allowed, retry}
+`)
+
+// TokenBucket is a Redis-backed token bucket limiter shared by all replicas
+type TokenBucket struct {
+	client   *redis.Client
+	prefix   string
+	capacity int
+	rate     float64
+}
+
+// NewTokenBucket creates a limiter that allows bursts of capacity requests and
+// refills at rate tokens per second
+func NewTokenBucket(client *redis.Client, prefix string, capacity int, rate float64) *TokenBucket {
+	return &TokenBucket{
+		client:   client,
+		prefix:   prefix,
+		capacity: capacity,
+		rate:     rate,
+	}
+}
+
+// Allow takes a token for key. When no token is available it returns false and
+// the time until the next token is available.
+func (b *TokenBucket) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	result, err := tokenBucketScript.Run(ctx, b.client, []string{b.prefix + key}, b.capacity, b.rate).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("rate limit check failed: %w", err)
+	}
+
+	if result[0] == 1 {
+		return true, 0, nil
+	}
+	return false, time.Duration(result[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,55 @@
+package redislock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseScript deletes the lock only if it is still held by the caller's token
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Lock is a Redis mutex held by one process at a time. The lock expires after
+// its TTL so a crashed holder cannot block others forever.
+type Lock struct {
+	client *redis.Client
+	key    string
+	token  string
+}
+
+// Acquire tries to take the lock at key for ttl. It returns nil without an
+// error if the lock is currently held elsewhere.
+func Acquire(ctx context.Context, client *redis.Client, key string, ttl time.Duration) (*Lock, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return &Lock{client: client, key: key, token: token}, nil
+}
+
+// Release gives up the lock if it is still held
+func (l *Lock) Release(ctx context.Context) error {
+	return releaseScript.Run(ctx, l.client, []string{l.key}, l.token).Err()
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
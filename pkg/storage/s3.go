@@ -0,0 +1,262 @@
+// Package storage is a minimal client for S3-compatible object storage such
+// as MinIO, signing requests with AWS Signature Version 4.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/meet-app/backend/internal/config"
+)
+
+var (
+	ErrObjectNotFound = errors.New("object not found")
+	ErrObjectTooLarge = errors.New("object is too large")
+)
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+	amzDateFormat    = "20060102T150405Z"
+	scopeDateFormat  = "20060102"
+)
+
+// Client talks to an S3-compatible server. Requests made by the server go to
+// the internal endpoint; presigned URLs and object URLs use the public one.
+type Client struct {
+	endpoint  *url.URL
+	publicURL *url.URL
+	accessKey string
+	secretKey string
+	region    string
+	http      *http.Client
+}
+
+// NewClient creates a client for the configured MinIO server
+func NewClient(cfg *config.MinIOConfig) (*Client, error) {
+	scheme := "http"
+	if cfg.UseSSL {
+		scheme = "https"
+	}
+	endpoint := &url.URL{Scheme: scheme, Host: cfg.Endpoint}
+
+	publicURL := endpoint
+	if cfg.PublicURL != "" {
+		parsed, err := url.Parse(strings.TrimRight(cfg.PublicURL, "/"))
+		if err != nil || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid MinIO public URL %q", cfg.PublicURL)
+		}
+		publicURL = parsed
+	}
+
+	return &Client{
+		endpoint:  endpoint,
+		publicURL: publicURL,
+		accessKey: cfg.AccessKey,
+		secretKey: cfg.SecretKey,
+		region:    cfg.Region,
+		http:      &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// PresignPut returns a URL that lets its holder upload the object with a
+// plain PUT until it expires
+func (c *Client) PresignPut(bucket, key string, expires time.Duration) (string, error) {
+	now := time.Now().UTC()
+	u := c.objectURL(c.publicURL, bucket, key)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", signingAlgorithm)
+	query.Set("X-Amz-Credential", c.accessKey+"/"+c.scope(now))
+	query.Set("X-Amz-Date", now.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonical := strings.Join([]string{
+		http.MethodPut,
+		u.EscapedPath(),
+		canonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	query.Set("X-Amz-Signature", c.signature(now, canonical))
+	u.RawQuery = canonicalQuery(query)
+	return u.String(), nil
+}
+
+// GetObject downloads an object, failing if it is larger than maxBytes
+func (c *Client) GetObject(ctx context.Context, bucket, key string, maxBytes int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.objectURL(c.endpoint, bucket, key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	c.sign(req, nil)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrObjectNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("storage: GET %s/%s returned %s", bucket, key, resp.Status)
+	}
+	if resp.ContentLength > maxBytes {
+		return nil, ErrObjectTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrObjectTooLarge
+	}
+	return data, nil
+}
+
+// PutObject uploads an object
+func (c *Client) PutObject(ctx context.Context, bucket, key, contentType string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.objectURL(c.endpoint, bucket, key).String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	c.sign(req, data)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("storage: PUT %s/%s returned %s", bucket, key, resp.Status)
+	}
+	return nil
+}
+
+// ObjectURL returns the public URL of an object
+func (c *Client) ObjectURL(bucket, key string) string {
+	return c.objectURL(c.publicURL, bucket, key).String()
+}
+
+func (c *Client) objectURL(base *url.URL, bucket, key string) *url.URL {
+	u := *base
+	u.Path = strings.TrimRight(base.Path, "/") + "/" + bucket + "/" + key
+	u.RawPath = strings.TrimRight(base.EscapedPath(), "/") + "/" + uriEncode(bucket, false) + "/" + uriEncode(key, false)
+	return &u
+}
+
+// sign adds header-based SigV4 authentication to a request
+func (c *Client) sign(req *http.Request, body []byte) {
+	now := time.Now().UTC()
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", now.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + payloadHash + "\n" +
+			"x-amz-date:" + now.Format(amzDateFormat) + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, c.accessKey, c.scope(now), signedHeaders, c.signature(now, canonical),
+	))
+}
+
+func (c *Client) scope(t time.Time) string {
+	return t.Format(scopeDateFormat) + "/" + c.region + "/s3/aws4_request"
+}
+
+// signature signs a canonical request made at t
+func (c *Client) signature(t time.Time, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		t.Format(amzDateFormat),
+		c.scope(t),
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.secretKey), t.Format(scopeDateFormat))
+	key = hmacSHA256(key, c.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalQuery encodes query parameters sorted by key as SigV4 requires
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		vs := append([]string(nil), values[k]...)
+		sort.Strings(vs)
+		for _, v := range vs {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything except unreserved characters and,
+// unless encodeSlash is set, slashes
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case 'A' <= ch && ch <= 'Z', 'a' <= ch && ch <= 'z', '0' <= ch && ch <= '9',
+			ch == '-', ch == '_', ch == '.', ch == '~':
+			b.WriteByte(ch)
+		case ch == '/' && !encodeSlash:
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,87 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 seed of the RFC 6238 Appendix B test vectors
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+// rfcVectors are the SHA-1 vectors of RFC 6238 Appendix B, cut to the last
+// six of their eight digits
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestCode(t *testing.T) {
+	for _, v := range rfcVectors {
+		code, err := Code(rfcSecret, Step(time.Unix(v.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code at %d: %v", v.unix, err)
+		}
+		if code != v.code {
+			t.Errorf("Code at %d = %s, want %s", v.unix, code, v.code)
+		}
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	for _, secret := range []string{"", "not base32!"} {
+		if _, err := Code(secret, 1); err != ErrInvalidSecret {
+			t.Errorf("Code(%q) error = %v, want ErrInvalidSecret", secret, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, v := range rfcVectors {
+		at := time.Unix(v.unix, 0)
+		step, ok := Validate(rfcSecret, v.code, at, 0)
+		if !ok {
+			t.Errorf("Validate rejected %s at %d", v.code, v.unix)
+			continue
+		}
+		if step != Step(at) {
+			t.Errorf("Validate at %d returned step %d, want %d", v.unix, step, Step(at))
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	// The code of 1111111109 belongs to the step before 1111111111's
+	previous := rfcVectors[1]
+	at := time.Unix(rfcVectors[2].unix, 0)
+
+	if _, ok := Validate(rfcSecret, previous.code, at, 0); ok {
+		t.Error("Validate accepted the previous step's code without skew")
+	}
+	step, ok := Validate(rfcSecret, previous.code, at, 1)
+	if !ok {
+		t.Fatal("Validate rejected the previous step's code with a skew of 1")
+	}
+	if step != Step(at)-1 {
+		t.Errorf("Validate returned step %d, want %d", step, Step(at)-1)
+	}
+}
+
+func TestValidateRejects(t *testing.T) {
+	at := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "287083", "abcdef"} {
+		if _, ok := Validate(rfcSecret, code, at, 1); ok {
+			t.Errorf("Validate accepted %q", code)
+		}
+	}
+	if _, ok := Validate("", "287082", at, 1); ok {
+		t.Error("Validate accepted a code for an empty secret")
+	}
+}